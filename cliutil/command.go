@@ -0,0 +1,308 @@
+package cliutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrHelp error on the help option is used, the help message has been printed.
+var ErrHelp = errors.New("cliutil: help requested")
+
+// Context of the running command
+type Context struct {
+	App *App
+	// Command the matched command
+	Command *Command
+	// Args the remaining positional arguments
+	Args []string
+}
+
+// Arg get positional argument by index. returns empty string on not exists.
+func (c *Context) Arg(i int) string {
+	if i >= 0 && i < len(c.Args) {
+		return c.Args[i]
+	}
+	return ""
+}
+
+// Command definition
+type Command struct {
+	// Name of the command
+	Name string
+	// Aliases of the command name
+	Aliases []string
+	// Desc short description for the commands list
+	Desc string
+	// Help the long help message, will show on the command help
+	Help string
+	// ArgsUsage the arguments usage. eg: "<src> [dst]"
+	ArgsUsage string
+	// Config a struct pointer, the fields will be bound as flags. see BindFlags()
+	Config interface{}
+	// Hidden not show on the commands list and completion
+	Hidden bool
+	// Run the command handler
+	Run func(c *Context) error
+
+	parent *Command
+	subs   []*Command
+	flags  []*Flag
+	bound  bool
+}
+
+// NewCommand create
+//
+// Usage:
+// 	cmd := cliutil.NewCommand("build", "build the project", func(c *cliutil.Command) {
+// 		c.Config = &BuildOpts{}
+// 		c.Run = func(ctx *cliutil.Context) error {
+// 			// do something ...
+// 			return nil
+// 		}
+// 	})
+func NewCommand(name, desc string, fns ...func(c *Command)) *Command {
+	c := &Command{Name: name, Desc: desc}
+	for _, fn := range fns {
+		fn(c)
+	}
+	return c
+}
+
+// Add sub commands
+func (c *Command) Add(subs ...*Command) *Command {
+	for _, sub := range subs {
+		sub.parent = c
+		c.subs = append(c.subs, sub)
+	}
+	return c
+}
+
+// Parent command, returns nil on the command is root
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+// Commands get sub commands
+func (c *Command) Commands() []*Command {
+	return c.subs
+}
+
+// Sub find sub command by name or alias
+func (c *Command) Sub(name string) *Command {
+	for _, sub := range c.subs {
+		if sub.HasName(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// HasName check the name is the command name or alias
+func (c *Command) HasName(name string) bool {
+	if c.Name == name {
+		return true
+	}
+
+	for _, alias := range c.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Path get the full command path. eg: "app remote add"
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+// Flags get the flags bound from the Config
+func (c *Command) Flags() ([]*Flag, error) {
+	if !c.bound && c.Config != nil {
+		flags, err := BindFlags(c.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		c.flags, c.bound = flags, true
+	}
+	return c.flags, nil
+}
+
+// find flag by name on the command and it's parents
+func (c *Command) lookupFlag(name string) *Flag {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for _, f := range cmd.flags {
+			if f.HasName(name) {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// App the CLI application, it's the root command.
+//
+// Usage:
+// 	app := cliutil.NewApp("myapp", "1.0.0", "my cli application")
+// 	app.Add(buildCmd, serveCmd)
+// 	app.MustRun()
+type App struct {
+	*Command
+	// Version of the app, will add the "--version" option on not empty.
+	Version string
+	// EnvPrefix for auto bind env var to the flags without env tag.
+	// eg: prefix "MYAPP_", flag "dry-run" will read env "MYAPP_DRY_RUN"
+	EnvPrefix string
+	// Out writer for help message. default is os.Stdout
+	Out io.Writer
+	// ErrOut writer for error message. default is os.Stderr
+	ErrOut io.Writer
+}
+
+// NewApp create
+func NewApp(name, version, desc string, fns ...func(a *App)) *App {
+	a := &App{
+		Command: NewCommand(name, desc),
+		Version: version,
+		Out:     os.Stdout,
+		ErrOut:  os.Stderr,
+	}
+
+	for _, fn := range fns {
+		fn(a)
+	}
+	return a
+}
+
+// MustRun run the app with os.Args, will exit on error.
+func (a *App) MustRun() {
+	if err := a.Run(os.Args[1:]); err != nil {
+		if err == ErrHelp {
+			os.Exit(0)
+		}
+
+		_, _ = fmt.Fprintf(a.ErrOut, "Error: %s\n", err.Error())
+		os.Exit(2)
+	}
+}
+
+// Run the app with args(without the bin name).
+//
+// returns ErrHelp on the help or version option is used.
+func (a *App) Run(args []string) error {
+	cmd, rest, err := a.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Run == nil {
+		a.printHelp(cmd)
+		return nil
+	}
+
+	return cmd.Run(&Context{App: a, Command: cmd, Args: rest})
+}
+
+// init flags of the command: bind the Config and apply env, default values.
+func (a *App) initFlags(cmd *Command) error {
+	flags, err := cmd.Flags()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range flags {
+		if err := f.init(a.EnvPrefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse the args, returns the matched command and positional arguments.
+func (a *App) parse(args []string) (*Command, []string, error) {
+	cmd := a.Command
+	if err := a.initFlags(cmd); err != nil {
+		return nil, nil, err
+	}
+
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+
+		// positional argument or sub command
+		if arg == "" || arg == "-" || arg[0] != '-' {
+			if len(rest) == 0 {
+				if sub := cmd.Sub(arg); sub != nil {
+					if err := a.initFlags(sub); err != nil {
+						return nil, nil, err
+					}
+					cmd = sub
+					continue
+				}
+
+				if len(cmd.subs) > 0 && cmd.Run == nil {
+					return nil, nil, a.unknownCommand(cmd, arg)
+				}
+			}
+
+			rest = append(rest, arg)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		val, hasVal := "", false
+		if pos := strings.IndexByte(name, '='); pos > 0 {
+			name, val, hasVal = name[:pos], name[pos+1:], true
+		}
+
+		// lookup the user flags first, they can override the builtin help and version flags
+		f := cmd.lookupFlag(name)
+		if f == nil {
+			switch {
+			case name == "h" || name == "help":
+				a.printHelp(cmd)
+				return nil, nil, ErrHelp
+			case name == "version" && a.Version != "":
+				_, _ = fmt.Fprintf(a.Out, "%s version %s\n", a.Name, a.Version)
+				return nil, nil, ErrHelp
+			}
+			return nil, nil, a.unknownFlag(cmd, arg)
+		}
+
+		if !hasVal {
+			if f.IsBool() {
+				val = "true"
+			} else if i+1 < len(args) {
+				i++
+				val = args[i]
+			} else {
+				return nil, nil, fmt.Errorf("flag needs an argument: %s", arg)
+			}
+		}
+
+		if err := f.setArg(val); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// check required flags
+	for c := cmd; c != nil; c = c.parent {
+		for _, f := range c.flags {
+			if f.Required && !f.IsSet() {
+				return nil, nil, fmt.Errorf("required flag --%s is not set", f.Name)
+			}
+		}
+	}
+	return cmd, rest, nil
+}
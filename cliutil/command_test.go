@@ -0,0 +1,271 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/cliutil"
+)
+
+type globalOpts struct {
+	Verbose bool `flag:"verbose,v" desc:"show more details"`
+}
+
+type buildOpts struct {
+	Output  string        `flag:"output,o" desc:"the output dir" default:"dist"`
+	Jobs    int           `flag:"jobs,j" default:"2" env:"TEST_BUILD_JOBS"`
+	Tags    []string      `flag:"tag,t" desc:"build tags"`
+	Timeout time.Duration `desc:"build timeout"`
+	DryRun  bool
+	ignored string
+}
+
+func newTestApp() (*cliutil.App, *globalOpts, *buildOpts, *[]string) {
+	gOpts, bOpts := &globalOpts{}, &buildOpts{}
+	var called []string
+
+	app := cliutil.NewApp("myapp", "1.0.1", "my cli app", func(a *cliutil.App) {
+		a.Out = new(bytes.Buffer)
+		a.Config = gOpts
+	})
+
+	app.Add(
+		cliutil.NewCommand("build", "build the project", func(c *cliutil.Command) {
+			c.Aliases = []string{"b"}
+			c.Config = bOpts
+			c.ArgsUsage = "[dir]"
+			c.Run = func(ctx *cliutil.Context) error {
+				called = append(called, ctx.Command.Path())
+				called = append(called, ctx.Args...)
+				return nil
+			}
+		}),
+		cliutil.NewCommand("remote", "manage remotes").Add(
+			cliutil.NewCommand("add", "add remote", func(c *cliutil.Command) {
+				c.Run = func(ctx *cliutil.Context) error {
+					called = append(called, ctx.Command.Path(), ctx.Arg(0), ctx.Arg(1))
+					return nil
+				}
+			}),
+		),
+	)
+	return app, gOpts, bOpts, &called
+}
+
+func TestApp_Run(t *testing.T) {
+	is := assert.New(t)
+
+	app, gOpts, bOpts, called := newTestApp()
+	err := app.Run([]string{"-v", "build", "-o", "out", "--jobs=4", "-t", "a", "--tag", "b", "--timeout", "3s", "--dry-run", "src", "--", "-x"})
+	is.NoError(err)
+	is.True(gOpts.Verbose)
+	is.Equal("out", bOpts.Output)
+	is.Equal(4, bOpts.Jobs)
+	is.Equal([]string{"a", "b"}, bOpts.Tags)
+	is.Equal(3*time.Second, bOpts.Timeout)
+	is.True(bOpts.DryRun)
+	is.Equal([]string{"myapp build", "src", "-x"}, *called)
+
+	// alias, default value and global flag after the sub command
+	app, gOpts, bOpts, called = newTestApp()
+	is.NoError(app.Run([]string{"b", "--verbose"}))
+	is.True(gOpts.Verbose)
+	is.Equal("dist", bOpts.Output)
+	is.Equal(2, bOpts.Jobs)
+	is.Equal([]string{"myapp build"}, *called)
+
+	// nested
+	app, _, _, called = newTestApp()
+	is.NoError(app.Run([]string{"remote", "add", "origin", "url"}))
+	is.Equal([]string{"myapp remote add", "origin", "url"}, *called)
+
+	// invalid value
+	app, _, _, _ = newTestApp()
+	err = app.Run([]string{"build", "--jobs", "abc"})
+	is.Error(err)
+	is.Contains(err.Error(), `invalid value "abc" for flag --jobs`)
+
+	app, _, _, _ = newTestApp()
+	err = app.Run([]string{"build", "--output"})
+	is.Error(err)
+	is.Contains(err.Error(), "flag needs an argument")
+
+	// empty arg is a positional argument
+	app, _, _, called = newTestApp()
+	is.NoError(app.Run([]string{"build", ""}))
+	is.Equal([]string{"myapp build", ""}, *called)
+}
+
+func TestApp_Run_repeat(t *testing.T) {
+	is := assert.New(t)
+
+	app, gOpts, bOpts, _ := newTestApp()
+	is.NoError(app.Run([]string{"-v", "build", "-t", "a", "-t", "b", "-o", "out"}))
+	is.Equal([]string{"a", "b"}, bOpts.Tags)
+
+	// the values and state are reset on run again
+	is.NoError(app.Run([]string{"build", "-t", "c"}))
+	is.False(gOpts.Verbose)
+	is.Equal("dist", bOpts.Output)
+	is.Equal([]string{"c"}, bOpts.Tags)
+
+	flags, err := app.Sub("build").Flags()
+	is.NoError(err)
+	is.NoError(app.Run([]string{"build"}))
+	is.Empty(bOpts.Tags)
+	for _, f := range flags {
+		is.False(f.Changed(), f.Name)
+	}
+}
+
+func TestApp_Run_env(t *testing.T) {
+	is := assert.New(t)
+	t.Setenv("TEST_BUILD_JOBS", "8")
+	t.Setenv("MYAPP_OUTPUT", "env-out")
+
+	app, _, bOpts, _ := newTestApp()
+	is.NoError(app.Run([]string{"build"}))
+	is.Equal(8, bOpts.Jobs)
+	is.Equal("dist", bOpts.Output)
+
+	// the cli args is first
+	app, _, bOpts, _ = newTestApp()
+	app.EnvPrefix = "MYAPP_"
+	is.NoError(app.Run([]string{"build", "-j", "3"}))
+	is.Equal(3, bOpts.Jobs)
+	is.Equal("env-out", bOpts.Output)
+}
+
+func TestApp_Run_required(t *testing.T) {
+	opts := &struct {
+		Token string `required:"true" env:"TEST_APP_TOKEN"`
+	}{}
+
+	app := cliutil.NewApp("myapp", "", "", func(a *cliutil.App) {
+		a.Config = opts
+		a.Command.Run = func(c *cliutil.Context) error { return nil }
+	})
+
+	err := app.Run(nil)
+	assert.Error(t, err)
+	assert.Equal(t, "required flag --token is not set", err.Error())
+
+	t.Setenv("TEST_APP_TOKEN", "abc")
+	assert.NoError(t, app.Run(nil))
+	assert.Equal(t, "abc", opts.Token)
+}
+
+func TestApp_Run_suggest(t *testing.T) {
+	is := assert.New(t)
+
+	app, _, _, _ := newTestApp()
+	err := app.Run([]string{"bulid"})
+	is.Error(err)
+	is.Contains(err.Error(), `unknown command "bulid" for "myapp"`)
+	is.Contains(err.Error(), "Did you mean this?\n\tbuild")
+
+	err = app.Run([]string{"remote", "ad"})
+	is.Error(err)
+	is.Contains(err.Error(), "Did you mean this?\n\tadd")
+
+	err = app.Run([]string{"build", "--ouput", "x"})
+	is.Error(err)
+	is.Contains(err.Error(), "unknown flag: --ouput")
+	is.Contains(err.Error(), "Did you mean this?\n\t--output")
+
+	err = app.Run([]string{"xyz"})
+	is.Error(err)
+	is.NotContains(err.Error(), "Did you mean")
+
+	is.Equal([]string{"build"}, cliutil.Suggest("buidl", []string{"build", "serve"}))
+	is.Empty(cliutil.Suggest("foo", []string{"build", "serve"}))
+}
+
+func TestApp_help(t *testing.T) {
+	is := assert.New(t)
+
+	app, _, _, called := newTestApp()
+	err := app.Run([]string{"build", "-h"})
+	is.Equal(cliutil.ErrHelp, err)
+	is.Empty(*called)
+
+	help := app.Out.(*bytes.Buffer).String()
+	is.Contains(help, "build the project")
+	is.Contains(help, "Usage:\n  myapp build [flags] [dir]")
+	is.Contains(help, "Aliases:\n  build, b")
+	is.Contains(help, `-o, --output string`)
+	is.Contains(help, `the output dir (default "dist")`)
+	is.Contains(help, "[$TEST_BUILD_JOBS]")
+	is.Contains(help, "    --dry-run")
+	is.Contains(help, "Global Flags:\n  -v, --verbose   show more details")
+
+	// no run func, show help
+	app, _, _, _ = newTestApp()
+	is.NoError(app.Run(nil))
+	help = app.Out.(*bytes.Buffer).String()
+	is.Contains(help, "Usage:\n  myapp <command> [flags]")
+	is.Contains(help, "Commands:\n  build, b")
+	is.Contains(help, "--version")
+
+	app, _, _, _ = newTestApp()
+	is.Equal(cliutil.ErrHelp, app.Run([]string{"--version"}))
+	is.Equal("myapp version 1.0.1\n", app.Out.(*bytes.Buffer).String())
+
+	// the user flag aliased h
+	opts := &struct {
+		Host string `flag:"host,h" desc:"the server host"`
+	}{}
+	app, _, _, called = newTestApp()
+	app.Add(cliutil.NewCommand("serve", "start server", func(c *cliutil.Command) {
+		c.Config = opts
+		c.Run = func(ctx *cliutil.Context) error {
+			*called = append(*called, ctx.Command.Path())
+			return nil
+		}
+	}))
+	is.NoError(app.Run([]string{"serve", "-h", "localhost"}))
+	is.Equal("localhost", opts.Host)
+	is.Equal([]string{"myapp serve"}, *called)
+
+	is.Equal(cliutil.ErrHelp, app.Run([]string{"serve", "--help"}))
+	help = app.Out.(*bytes.Buffer).String()
+	is.Contains(help, "-h, --host string")
+	is.Contains(help, "    --help")
+	is.NotContains(help, "-h, --help")
+}
+
+func TestApp_CompletionScript(t *testing.T) {
+	is := assert.New(t)
+
+	app, _, _, _ := newTestApp()
+	app.AddCompletionCommand()
+
+	script, err := app.CompletionScript("bash")
+	is.NoError(err)
+	is.Contains(script, "_myapp_completions() {")
+	is.Contains(script, `"") words="build remote completion --verbose -v --help" ;;`)
+	is.Contains(script, `"remote add") words="--verbose -v --help" ;;`)
+	is.Contains(script, `"build"|"b") words=`)
+	is.Contains(script, `local value_flags=" --output -o --jobs -j --tag -t --timeout "`)
+	is.Contains(script, "complete -o default -F _myapp_completions myapp")
+
+	script, err = app.CompletionScript("zsh")
+	is.NoError(err)
+	is.True(strings.HasPrefix(script, "#compdef myapp"))
+
+	script, err = app.CompletionScript("fish")
+	is.NoError(err)
+	is.Contains(script, "complete -c myapp -f -n '__fish_use_subcommand' -a build -d 'build the project'")
+	is.Contains(script, "complete -c myapp -l verbose -s v -d 'show more details'")
+	is.Contains(script, "complete -c myapp -n '__fish_seen_subcommand_from build' -l output -s o -r -d 'the output dir'")
+
+	_, err = app.CompletionScript("cmd")
+	is.Error(err)
+
+	out := app.Out.(*bytes.Buffer)
+	is.NoError(app.Run([]string{"completion", "fish"}))
+	is.Contains(out.String(), "# fish completion for myapp")
+}
@@ -0,0 +1,202 @@
+package cliutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompletionScript generate the shell completion script for the app.
+// supported shells: bash, zsh, fish
+//
+// Usage:
+// 	script, err := app.CompletionScript("bash")
+// 	// install: myapp completion bash > /etc/bash_completion.d/myapp
+func (a *App) CompletionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return a.bashCompletion(), nil
+	case "zsh":
+		// use the bash completion by bashcompinit
+		return "#compdef " + a.Name + "\n\nautoload -U +X bashcompinit && bashcompinit\n\n" + a.bashCompletion(), nil
+	case "fish":
+		return a.fishCompletion(), nil
+	}
+	return "", fmt.Errorf("cliutil: unsupported shell %q for completion", shell)
+}
+
+// AddCompletionCommand add the "completion" command for print completion script.
+//
+// Usage:
+// 	myapp completion bash
+func (a *App) AddCompletionCommand() *App {
+	a.Add(NewCommand("completion", "generate the shell completion script", func(c *Command) {
+		c.ArgsUsage = "<bash|zsh|fish>"
+		c.Run = func(ctx *Context) error {
+			script, err := a.CompletionScript(ctx.Arg(0))
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprint(a.Out, script)
+			return err
+		}
+	}))
+	return a
+}
+
+// walk all visible commands, the path not contains the app name.
+func walkCommands(cmd *Command, path []string, fn func(c *Command, path []string)) {
+	fn(cmd, path)
+	for _, sub := range cmd.subs {
+		if !sub.Hidden {
+			walkCommands(sub, append(path[:len(path):len(path)], sub.Name), fn)
+		}
+	}
+}
+
+// words for complete of the command: sub command names and flag names
+func completionWords(cmd *Command) (subs []string, flags []string) {
+	for _, sub := range cmd.subs {
+		if !sub.Hidden {
+			subs = append(subs, sub.Name)
+		}
+	}
+
+	for c := cmd; c != nil; c = c.parent {
+		fs, _ := c.Flags()
+		for _, f := range fs {
+			flags = append(flags, f.Names()...)
+		}
+	}
+	return subs, append(flags, "--help")
+}
+
+// all paths of the command by the names and aliases, the path not contains the app name.
+func commandPaths(cmd *Command) []string {
+	if cmd.parent == nil {
+		return []string{""}
+	}
+
+	var paths []string
+	for _, p := range commandPaths(cmd.parent) {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			paths = append(paths, strings.TrimPrefix(p+" "+name, " "))
+		}
+	}
+	return paths
+}
+
+// names of the visible commands flags, which need a value
+func valueFlagNames(root *Command) (names []string) {
+	seen := make(map[string]bool)
+	walkCommands(root, nil, func(c *Command, _ []string) {
+		fs, _ := c.Flags()
+		for _, f := range fs {
+			if f.IsBool() {
+				continue
+			}
+
+			for _, name := range f.Names() {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	})
+	return
+}
+
+func (a *App) bashCompletion() string {
+	funcName := "_" + strings.Replace(a.Name, "-", "_", -1) + "_completions"
+
+	var sb strings.Builder
+	sb.WriteString("# bash completion for " + a.Name + "\n")
+	sb.WriteString(funcName + "() {\n")
+	sb.WriteString("    local cur path i word\n")
+	sb.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	sb.WriteString("    path=\"\"\n")
+	// the flags need a value, the next word is skipped
+	sb.WriteString(fmt.Sprintf("    local value_flags=%q\n", " "+strings.Join(valueFlagNames(a.Command), " ")+" "))
+	sb.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	sb.WriteString("        word=\"${COMP_WORDS[i]}\"\n")
+	sb.WriteString("        if [[ \"$word\" == -* ]]; then\n")
+	sb.WriteString("            [[ \"$value_flags\" == *\" $word \"* ]] && ((i++))\n")
+	sb.WriteString("            continue\n")
+	sb.WriteString("        fi\n")
+	sb.WriteString("        path=\"$path $word\"\n")
+	sb.WriteString("    done\n\n")
+	sb.WriteString("    local words\n")
+	sb.WriteString("    case \"${path# }\" in\n")
+
+	walkCommands(a.Command, nil, func(c *Command, path []string) {
+		subs, flags := completionWords(c)
+		var patterns []string
+		for _, p := range commandPaths(c) {
+			patterns = append(patterns, fmt.Sprintf("%q", p))
+		}
+		sb.WriteString(fmt.Sprintf("    %s) words=%q ;;\n", strings.Join(patterns, "|"), strings.Join(append(subs, flags...), " ")))
+	})
+
+	sb.WriteString("    *) words=\"\" ;;\n")
+	sb.WriteString("    esac\n\n")
+	sb.WriteString("    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("complete -o default -F " + funcName + " " + a.Name + "\n")
+	return sb.String()
+}
+
+func (a *App) fishCompletion() string {
+	var sb strings.Builder
+	sb.WriteString("# fish completion for " + a.Name + "\n")
+
+	walkCommands(a.Command, nil, func(c *Command, path []string) {
+		// condition for complete sub commands of the command
+		cond, flagCond := "__fish_use_subcommand", ""
+		if len(path) > 0 {
+			flagCond = "__fish_seen_subcommand_from " + path[len(path)-1]
+			cond = flagCond
+
+			var subNames []string
+			for _, sub := range c.subs {
+				subNames = append(subNames, sub.Name)
+			}
+			if len(subNames) > 0 {
+				cond += "; and not __fish_seen_subcommand_from " + strings.Join(subNames, " ")
+			}
+		}
+
+		for _, sub := range c.subs {
+			if !sub.Hidden {
+				sb.WriteString(fmt.Sprintf("complete -c %s -f -n '%s' -a %s -d %s\n", a.Name, cond, sub.Name, fishQuote(sub.Desc)))
+			}
+		}
+
+		fs, _ := c.Flags()
+		for _, f := range fs {
+			// flags of the root command is available for all commands
+			line := "complete -c " + a.Name
+			if flagCond != "" {
+				line += " -n '" + flagCond + "'"
+			}
+
+			for _, name := range append([]string{f.Name}, f.Aliases...) {
+				if len(name) == 1 {
+					line += " -s " + name
+				} else {
+					line += " -l " + name
+				}
+			}
+
+			if !f.IsBool() {
+				line += " -r"
+			}
+			sb.WriteString(line + " -d " + fishQuote(f.Desc) + "\n")
+		}
+	})
+	return sb.String()
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "\\'", -1) + "'"
+}
@@ -0,0 +1,10 @@
+package cliutil
+
+// MockTerminal mock the terminal check, returns the func for restore it.
+func MockTerminal(fn func(v interface{}) bool) func() {
+	old := isTerminal
+	isTerminal = fn
+	return func() {
+		isTerminal = old
+	}
+}
@@ -0,0 +1,291 @@
+package cliutil
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urionz/goutil/envutil"
+	"github.com/urionz/goutil/strutil"
+)
+
+// Flag a command option, it's bound to a struct field.
+//
+// Field tags:
+// 	flag     - flag names, first is long name, others are aliases. eg: `flag:"output,o"`.
+// 	           use `flag:"-"` to ignore the field. default is kebab case of the field name.
+// 	desc     - description message for help
+// 	default  - default value
+// 	env      - env var name for fallback value. eg: `env:"APP_OUTPUT"`
+// 	required - mark the flag is required. eg: `required:"true"`
+type Flag struct {
+	Name     string
+	Aliases  []string
+	Desc     string
+	Default  string
+	Env      string
+	Required bool
+
+	// has been set by cli args or env or default value
+	isSet bool
+	// has been set by cli args
+	changed bool
+	value   reflect.Value
+	// the origin value of the field, restored before each parse
+	origin reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// IsBool check the flag is bool type
+func (f *Flag) IsBool() bool {
+	return f.value.Kind() == reflect.Bool
+}
+
+// IsSlice check the flag is slice type, can be set multi times
+func (f *Flag) IsSlice() bool {
+	return f.value.Kind() == reflect.Slice
+}
+
+// IsSet check the flag value has been set by cli args, env or default value
+func (f *Flag) IsSet() bool {
+	return f.isSet
+}
+
+// Changed check the flag value has been set by cli args
+func (f *Flag) Changed() bool {
+	return f.changed
+}
+
+// TypeName get the flag value type name for help. eg: "string", "int"
+func (f *Flag) TypeName() string {
+	if f.value.Type() == durationType {
+		return "duration"
+	}
+
+	if f.IsSlice() {
+		return f.value.Type().Elem().Kind().String() + "s"
+	}
+	return f.value.Kind().String()
+}
+
+// Names get all names of the flag with prefix "-" or "--"
+func (f *Flag) Names() []string {
+	names := make([]string, 0, len(f.Aliases)+1)
+	for _, name := range append([]string{f.Name}, f.Aliases...) {
+		if len(name) == 1 {
+			names = append(names, "-"+name)
+		} else {
+			names = append(names, "--"+name)
+		}
+	}
+	return names
+}
+
+// HasName check the name is the flag name or alias
+func (f *Flag) HasName(name string) bool {
+	if f.Name == name {
+		return true
+	}
+
+	for _, alias := range f.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Set the flag value by string. slice flag will append the value.
+func (f *Flag) Set(val string) error {
+	if f.IsSlice() {
+		item := reflect.New(f.value.Type().Elem()).Elem()
+		if err := setValue(item, val); err != nil {
+			return f.wrapErr(val, err)
+		}
+
+		f.value.Set(reflect.Append(f.value, item))
+	} else if err := setValue(f.value, val); err != nil {
+		return f.wrapErr(val, err)
+	}
+
+	f.isSet = true
+	return nil
+}
+
+// set value from cli args. the slice value from env or default will be reset.
+func (f *Flag) setArg(val string) error {
+	if f.IsSlice() && !f.changed {
+		f.value.Set(reflect.Zero(f.value.Type()))
+	}
+
+	f.changed = true
+	return f.Set(val)
+}
+
+func (f *Flag) wrapErr(val string, err error) error {
+	return fmt.Errorf("invalid value %q for flag --%s: %s", val, f.Name, err.Error())
+}
+
+// clone the value, the slice will not share the underlying array
+func cloneValue(rv reflect.Value) reflect.Value {
+	cp := reflect.New(rv.Type()).Elem()
+	if rv.Kind() == reflect.Slice && !rv.IsNil() {
+		cp.Set(reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len()))
+		reflect.Copy(cp, rv)
+	} else {
+		cp.Set(rv)
+	}
+	return cp
+}
+
+func setValue(rv reflect.Value, val string) error {
+	if rv.Type() == durationType {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+
+		rv.SetInt(int64(d))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(val)
+	case reflect.Bool:
+		b, err := strutil.ToBool(val)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 0, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(val, 0, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported flag type %s", rv.Type())
+	}
+	return nil
+}
+
+// reset the value and state, then apply the default value and env value.
+func (f *Flag) init(envPrefix string) error {
+	f.value.Set(cloneValue(f.origin))
+	f.isSet, f.changed = false, false
+
+	envName := f.Env
+	if envName == "" && envPrefix != "" {
+		envName = envPrefix + strings.ToUpper(strings.Replace(f.Name, "-", "_", -1))
+	}
+
+	if envName != "" {
+		if val := envutil.Getenv(envName); val != "" {
+			// env value split by comma for slice flag
+			if f.IsSlice() {
+				for _, item := range strings.Split(val, ",") {
+					if err := f.Set(strings.TrimSpace(item)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			return f.Set(val)
+		}
+	}
+
+	if f.Default != "" {
+		if f.IsSlice() {
+			for _, item := range strings.Split(f.Default, ",") {
+				if err := f.Set(strings.TrimSpace(item)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return f.Set(f.Default)
+	}
+	return nil
+}
+
+// BindFlags parse flags from the struct pointer fields.
+//
+// Usage:
+// 	type BuildOpts struct {
+// 		Output  string   `flag:"output,o" desc:"the output dir" default:"dist"`
+// 		Verbose bool     `flag:"verbose,v" desc:"show more details"`
+// 		Tags    []string `flag:"tag,t" desc:"build tags, can be set multi times"`
+// 		Token   string   `env:"APP_TOKEN" required:"true"`
+// 	}
+// 	flags, err := BindFlags(&BuildOpts{})
+func BindFlags(ptr interface{}) ([]*Flag, error) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cliutil: flags config must be a struct pointer, but got %T", ptr)
+	}
+
+	var flags []*Flag
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("flag")
+		if tag == "-" || sf.PkgPath != "" { // ignored or not exported
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && fv.Kind() == reflect.Struct {
+			subs, err := BindFlags(fv.Addr().Interface())
+			if err != nil {
+				return nil, err
+			}
+			flags = append(flags, subs...)
+			continue
+		}
+
+		names := strutil.Split(tag, ",")
+		if len(names) == 0 {
+			names = []string{strutil.ToKebab(sf.Name)}
+		}
+
+		f := &Flag{
+			Name:     names[0],
+			Aliases:  names[1:],
+			Desc:     sf.Tag.Get("desc"),
+			Default:  sf.Tag.Get("default"),
+			Env:      sf.Tag.Get("env"),
+			Required: sf.Tag.Get("required") == "true",
+			value:    fv,
+			origin:   cloneValue(fv),
+		}
+
+		// check the type is supported
+		kind := fv.Kind()
+		if kind == reflect.Slice {
+			kind = fv.Type().Elem().Kind()
+		}
+		if kind == reflect.Map || kind == reflect.Struct || kind == reflect.Ptr || kind == reflect.Interface || kind == reflect.Slice {
+			return nil, fmt.Errorf("cliutil: unsupported flag type %s of the field %s", fv.Type(), sf.Name)
+		}
+
+		flags = append(flags, f)
+	}
+	return flags, nil
+}
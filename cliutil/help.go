@@ -0,0 +1,192 @@
+package cliutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urionz/goutil/strutil"
+)
+
+// SuggestMaxRate the max differ rate for "did you mean" suggestions. see strutil.Similarity()
+var SuggestMaxRate float32 = 0.4
+
+// Suggest find similar names of the input, the prefix matched names are also returned.
+//
+// Usage:
+// 	Suggest("bulid", []string{"build", "serve"}) // []string{"build"}
+func Suggest(input string, names []string) []string {
+	var ss []string
+	for _, name := range names {
+		if strings.HasPrefix(name, input) {
+			ss = append(ss, name)
+			continue
+		}
+
+		// NOTICE: the lower differ rate is more similar
+		if rate, _ := strutil.Similarity(input, name, 0); rate <= SuggestMaxRate {
+			ss = append(ss, name)
+		}
+	}
+	return ss
+}
+
+func (a *App) unknownCommand(cmd *Command, name string) error {
+	var names []string
+	for _, sub := range cmd.subs {
+		if !sub.Hidden {
+			names = append(names, sub.Name)
+			names = append(names, sub.Aliases...)
+		}
+	}
+
+	msg := fmt.Sprintf("unknown command %q for %q", name, cmd.Path())
+	if ss := Suggest(name, names); len(ss) > 0 {
+		msg += "\n\nDid you mean this?\n\t" + strings.Join(ss, "\n\t")
+	}
+	return fmt.Errorf("%s\n\nRun '%s --help' for usage.", msg, cmd.Path())
+}
+
+func (a *App) unknownFlag(cmd *Command, arg string) error {
+	var names []string
+	for c := cmd; c != nil; c = c.parent {
+		for _, f := range c.flags {
+			if len(f.Name) > 1 {
+				names = append(names, f.Name)
+			}
+		}
+	}
+
+	msg := "unknown flag: " + arg
+	if ss := Suggest(strings.TrimLeft(arg, "-"), names); len(ss) > 0 {
+		msg += "\n\nDid you mean this?\n\t--" + strings.Join(ss, "\n\t--")
+	}
+	return fmt.Errorf("%s\n\nRun '%s --help' for usage.", msg, cmd.Path())
+}
+
+// Usage get the usage line of the command. eg: "app build [flags] <src>"
+func (c *Command) Usage() string {
+	ss := []string{c.Path()}
+	if len(c.subs) > 0 {
+		ss = append(ss, "<command>")
+	}
+
+	ss = append(ss, "[flags]")
+	if c.ArgsUsage != "" {
+		ss = append(ss, c.ArgsUsage)
+	}
+	return strings.Join(ss, " ")
+}
+
+// HelpText generate the help message of the command
+func (a *App) HelpText(cmd *Command) string {
+	var sb strings.Builder
+	if cmd.Help != "" {
+		sb.WriteString(strings.TrimSpace(cmd.Help) + "\n\n")
+	} else if cmd.Desc != "" {
+		sb.WriteString(cmd.Desc + "\n\n")
+	}
+
+	sb.WriteString("Usage:\n  " + cmd.Usage() + "\n")
+
+	if len(cmd.Aliases) > 0 {
+		sb.WriteString("\nAliases:\n  " + strings.Join(append([]string{cmd.Name}, cmd.Aliases...), ", ") + "\n")
+	}
+
+	// sub commands
+	var rows [][2]string
+	for _, sub := range cmd.subs {
+		if !sub.Hidden {
+			rows = append(rows, [2]string{strings.Join(append([]string{sub.Name}, sub.Aliases...), ", "), sub.Desc})
+		}
+	}
+	if len(rows) > 0 {
+		sb.WriteString("\nCommands:\n")
+		writeRows(&sb, rows)
+	}
+
+	// flags
+	rows = flagRows(cmd)
+	helpName := "-h, --help"
+	if cmd.lookupFlag("h") != nil { // the -h is used by user flag
+		helpName = "    --help"
+	}
+	rows = append(rows, [2]string{helpName, "show help for " + cmd.Name})
+	if cmd.parent == nil && a.Version != "" && cmd.lookupFlag("version") == nil {
+		rows = append(rows, [2]string{"    --version", "show the version"})
+	}
+	sb.WriteString("\nFlags:\n")
+	writeRows(&sb, rows)
+
+	// inherited flags of the parent commands
+	rows = rows[:0]
+	for c := cmd.parent; c != nil; c = c.parent {
+		rows = append(rows, flagRows(c)...)
+	}
+	if len(rows) > 0 {
+		sb.WriteString("\nGlobal Flags:\n")
+		writeRows(&sb, rows)
+	}
+
+	if len(cmd.subs) > 0 {
+		sb.WriteString(fmt.Sprintf("\nUse \"%s <command> --help\" for more information about a command.\n", cmd.Path()))
+	}
+	return sb.String()
+}
+
+func (a *App) printHelp(cmd *Command) {
+	_, _ = fmt.Fprint(a.Out, a.HelpText(cmd))
+}
+
+func flagRows(cmd *Command) [][2]string {
+	flags, _ := cmd.Flags()
+	rows := make([][2]string, 0, len(flags))
+	for _, f := range flags {
+		var short, long []string
+		for _, name := range f.Names() {
+			if strings.HasPrefix(name, "--") {
+				long = append(long, name)
+			} else {
+				short = append(short, name)
+			}
+		}
+
+		// align the long names
+		names := strings.Join(append(short, long...), ", ")
+		if len(short) == 0 {
+			names = "    " + names
+		}
+		if !f.IsBool() {
+			names += " " + f.TypeName()
+		}
+
+		desc := f.Desc
+		if f.Default != "" {
+			desc += fmt.Sprintf(" (default %q)", f.Default)
+		}
+		if f.Env != "" {
+			desc += " [$" + f.Env + "]"
+		}
+		if f.Required {
+			desc += " (required)"
+		}
+		rows = append(rows, [2]string{names, strings.TrimSpace(desc)})
+	}
+	return rows
+}
+
+func writeRows(sb *strings.Builder, rows [][2]string) {
+	width := 0
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+
+	for _, row := range rows {
+		if row[1] == "" {
+			sb.WriteString("  " + row[0] + "\n")
+		} else {
+			sb.WriteString("  " + row[0] + strings.Repeat(" ", width-len(row[0])+3) + row[1] + "\n")
+		}
+	}
+}
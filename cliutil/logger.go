@@ -0,0 +1,334 @@
+package cliutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urionz/color"
+	"github.com/urionz/goutil/envutil"
+)
+
+// Level for the console logger
+type Level int
+
+// log levels of the console logger
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelSuccess
+	LevelWarn
+	LevelError
+	// LevelQuiet disable all logs
+	LevelQuiet
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:   "DEBUG",
+	LevelInfo:    "INFO",
+	LevelSuccess: "SUCCESS",
+	LevelWarn:    "WARN",
+	LevelError:   "ERROR",
+	LevelQuiet:   "QUIET",
+}
+
+// LevelStyles color styles for the level names
+var LevelStyles = map[Level]color.Style{
+	LevelDebug:   {color.FgGray},
+	LevelInfo:    {color.FgCyan},
+	LevelSuccess: {color.FgGreen, color.OpBold},
+	LevelWarn:    {color.FgYellow, color.OpBold},
+	LevelError:   {color.FgRed, color.OpBold},
+}
+
+// String get level name
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// ParseLevel parse level by name. eg: "debug", "WARN"
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "WARNING":
+		return LevelWarn, nil
+	case "OFF", "NONE":
+		return LevelQuiet, nil
+	}
+
+	for level, levelName := range levelNames {
+		if strings.EqualFold(levelName, name) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("cliutil: invalid log level %q", name)
+}
+
+// LevelEnv the env name for set the default level of the logger
+var LevelEnv = "LOG_LEVEL"
+
+// Logger a leveled console logger, it's safe for concurrent use.
+//
+// The color will be disabled on set env NO_COLOR or envutil.IsSupportColor() returns false.
+//
+// Usage:
+// 	log := cliutil.NewLogger(func(l *cliutil.Logger) {
+// 		l.TimeFormat = "15:04:05"
+// 	})
+// 	log.Infof("build %s", name)
+// 	log.WithTag("deploy").Success("all done")
+type Logger struct {
+	// Level the min level for output. default is LevelInfo, or read from env LevelEnv.
+	Level Level
+	// Out writer for console. default is os.Stderr
+	Out io.Writer
+	// NoColor disable color
+	NoColor bool
+	// TimeFormat for the timestamp. default is empty, not show timestamp on console.
+	TimeFormat string
+	// Tag prefix for the messages. eg: "build" will output "[build]"
+	Tag string
+
+	// shared by the tagged loggers
+	core *loggerCore
+}
+
+type loggerCore struct {
+	mu   sync.Mutex
+	file io.WriteCloser
+}
+
+// NewLogger create console logger
+func NewLogger(fns ...func(l *Logger)) *Logger {
+	l := &Logger{
+		Level: LevelInfo,
+		Out:   os.Stderr,
+		core:  &loggerCore{},
+	}
+
+	if name := envutil.Getenv(LevelEnv); name != "" {
+		if level, err := ParseLevel(name); err == nil {
+			l.Level = level
+		}
+	}
+
+	for _, fn := range fns {
+		fn(l)
+	}
+	return l
+}
+
+// WithTag create a new logger with the tag, it's shares the output and log file.
+func (l *Logger) WithTag(tag string) *Logger {
+	nl := *l
+	nl.Tag = tag
+	return &nl
+}
+
+// SetVerbosity set level by verbosity count. eg: count of the "-v" option.
+//
+// 	verbosity < 0: LevelWarn
+// 	verbosity = 0: LevelInfo
+// 	verbosity > 0: LevelDebug
+func (l *Logger) SetVerbosity(verbosity int) {
+	switch {
+	case verbosity < 0:
+		l.Level = LevelWarn
+	case verbosity > 0:
+		l.Level = LevelDebug
+	default:
+		l.Level = LevelInfo
+	}
+}
+
+// SetLogFile also write plain logs to the file, the logs will be appended.
+func (l *Logger) SetLogFile(fpath string) error {
+	f, err := os.OpenFile(fpath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0664)
+	if err != nil {
+		return err
+	}
+
+	l.SetLogWriter(f)
+	return nil
+}
+
+// SetLogWriter also write plain logs to the writer. the old writer will be closed.
+func (l *Logger) SetLogWriter(w io.WriteCloser) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	if l.core.file != nil {
+		_ = l.core.file.Close()
+	}
+	l.core.file = w
+}
+
+// Close the log file
+func (l *Logger) Close() error {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	if l.core.file == nil {
+		return nil
+	}
+
+	err := l.core.file.Close()
+	l.core.file = nil
+	return err
+}
+
+// Enabled check the level is enabled
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.Level && level < LevelQuiet
+}
+
+func (l *Logger) useColor() bool {
+	return !l.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(l.Out) && envutil.IsSupportColor()
+}
+
+// Log message with level
+func (l *Logger) Log(level Level, args ...interface{}) {
+	if l.Enabled(level) {
+		l.write(level, fmt.Sprint(args...))
+	}
+}
+
+// Logf format message with level
+func (l *Logger) Logf(level Level, format string, args ...interface{}) {
+	if l.Enabled(level) {
+		l.write(level, fmt.Sprintf(format, args...))
+	}
+}
+
+func (l *Logger) write(level Level, msg string) {
+	now := time.Now()
+	msg = strings.TrimRight(msg, "\n")
+
+	var ss []string
+	if l.TimeFormat != "" {
+		ss = append(ss, now.Format(l.TimeFormat))
+	}
+
+	name := fmt.Sprintf("%-7s", level.String())
+	useColor := l.useColor()
+	if style := LevelStyles[level]; useColor && len(style) > 0 {
+		name = fmt.Sprintf(color.FullColorTpl, style.Code(), name)
+	}
+	ss = append(ss, name)
+
+	tag := ""
+	if l.Tag != "" {
+		tag = "[" + l.Tag + "]"
+		if useColor {
+			ss = append(ss, fmt.Sprintf(color.FullColorTpl, color.FgMagenta.Code(), tag))
+		} else {
+			ss = append(ss, tag)
+		}
+	}
+	ss = append(ss, msg)
+
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	_, _ = fmt.Fprintln(l.Out, strings.Join(ss, " "))
+
+	// the log file always with timestamp and without color
+	if l.core.file != nil {
+		line := now.Format("2006-01-02 15:04:05") + " " + fmt.Sprintf("%-7s", level.String())
+		if tag != "" {
+			line += " " + tag
+		}
+		_, _ = fmt.Fprintln(l.core.file, line+" "+color.ClearCode(msg))
+	}
+}
+
+// Debug log debug message
+func (l *Logger) Debug(args ...interface{}) {
+	l.Log(LevelDebug, args...)
+}
+
+// Debugf log debug message
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.Logf(LevelDebug, format, args...)
+}
+
+// Info log info message
+func (l *Logger) Info(args ...interface{}) {
+	l.Log(LevelInfo, args...)
+}
+
+// Infof log info message
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.Logf(LevelInfo, format, args...)
+}
+
+// Success log success message
+func (l *Logger) Success(args ...interface{}) {
+	l.Log(LevelSuccess, args...)
+}
+
+// Successf log success message
+func (l *Logger) Successf(format string, args ...interface{}) {
+	l.Logf(LevelSuccess, format, args...)
+}
+
+// Warn log warning message
+func (l *Logger) Warn(args ...interface{}) {
+	l.Log(LevelWarn, args...)
+}
+
+// Warnf log warning message
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.Logf(LevelWarn, format, args...)
+}
+
+// Error log error message
+func (l *Logger) Error(args ...interface{}) {
+	l.Log(LevelError, args...)
+}
+
+// Errorf log error message
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.Logf(LevelError, format, args...)
+}
+
+// LogFlags the common flags for control the logger, can be embedded in the command Config.
+//
+// Usage:
+// 	type Opts struct {
+// 		cliutil.LogFlags
+// 		Name string
+// 	}
+// 	// on the command run
+// 	err := opts.Apply(logger)
+type LogFlags struct {
+	Verbose  bool   `flag:"verbose,v" desc:"show debug messages"`
+	Quiet    bool   `flag:"quiet,q" desc:"only show warning and error messages"`
+	LogLevel string `flag:"log-level" desc:"the log level: debug, info, success, warn, error, quiet"`
+	LogFile  string `flag:"log-file" desc:"also write plain logs to the file"`
+}
+
+// Apply the flags to the logger
+func (lf *LogFlags) Apply(l *Logger) error {
+	if lf.LogLevel != "" {
+		level, err := ParseLevel(lf.LogLevel)
+		if err != nil {
+			return err
+		}
+		l.Level = level
+	} else if lf.Verbose {
+		l.SetVerbosity(1)
+	} else if lf.Quiet {
+		l.SetVerbosity(-1)
+	}
+
+	if lf.LogFile != "" {
+		return l.SetLogFile(lf.LogFile)
+	}
+	return nil
+}
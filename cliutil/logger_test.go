@@ -0,0 +1,145 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/cliutil"
+)
+
+func TestParseLevel(t *testing.T) {
+	is := assert.New(t)
+
+	level, err := cliutil.ParseLevel("debug")
+	is.NoError(err)
+	is.Equal(cliutil.LevelDebug, level)
+
+	level, err = cliutil.ParseLevel("Warning")
+	is.NoError(err)
+	is.Equal(cliutil.LevelWarn, level)
+	is.Equal("WARN", level.String())
+
+	_, err = cliutil.ParseLevel("invalid")
+	is.Error(err)
+}
+
+func TestLogger(t *testing.T) {
+	is := assert.New(t)
+	t.Setenv("LOG_LEVEL", "")
+
+	buf := new(bytes.Buffer)
+	l := cliutil.NewLogger(func(l *cliutil.Logger) {
+		l.Out = buf
+		l.NoColor = true
+	})
+
+	l.Debug("debug message")
+	l.Info("info message")
+	l.WithTag("build").Successf("built %d files", 3)
+	l.Warn("warn message")
+	l.Error("error message")
+
+	is.Equal(strings.Join([]string{
+		"INFO    info message",
+		"SUCCESS [build] built 3 files",
+		"WARN    warn message",
+		"ERROR   error message",
+		"",
+	}, "\n"), buf.String())
+
+	buf.Reset()
+	l.SetVerbosity(1)
+	l.Debug("debug message")
+	is.Equal("DEBUG   debug message\n", buf.String())
+
+	buf.Reset()
+	l.SetVerbosity(-1)
+	l.Info("info message")
+	l.Warn("warn message")
+	is.Equal("WARN    warn message\n", buf.String())
+
+	buf.Reset()
+	l.Level = cliutil.LevelQuiet
+	l.Error("error message")
+	is.Empty(buf.String())
+}
+
+func TestLogger_color(t *testing.T) {
+	is := assert.New(t)
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("NO_COLOR", "")
+
+	buf := new(bytes.Buffer)
+	l := cliutil.NewLogger(func(l *cliutil.Logger) {
+		l.Out = buf
+		l.TimeFormat = "2006"
+	})
+
+	// the output is not a terminal
+	l.Error("error message")
+	is.NotContains(buf.String(), "\x1b[")
+
+	restore := cliutil.MockTerminal(func(v interface{}) bool {
+		return v == buf
+	})
+	defer restore()
+
+	buf.Reset()
+	l.Error("error message")
+	is.Contains(buf.String(), "\x1b[31;1mERROR  \x1b[0m error message")
+
+	buf.Reset()
+	t.Setenv("NO_COLOR", "1")
+	l.Error("error message")
+	is.NotContains(buf.String(), "\x1b[")
+}
+
+func TestLogger_env(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "error")
+
+	l := cliutil.NewLogger()
+	assert.Equal(t, cliutil.LevelError, l.Level)
+	assert.False(t, l.Enabled(cliutil.LevelWarn))
+}
+
+func TestLogFlags_Apply(t *testing.T) {
+	is := assert.New(t)
+	t.Setenv("LOG_LEVEL", "")
+
+	opts := &struct {
+		cliutil.LogFlags
+		Name string
+	}{}
+
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	app := cliutil.NewApp("myapp", "", "", func(a *cliutil.App) {
+		a.Config = opts
+	})
+	app.Command.Run = func(c *cliutil.Context) error {
+		return nil
+	}
+	is.NoError(app.Run([]string{"-v", "--log-file", logFile, "--name", "inhere"}))
+	is.Equal("inhere", opts.Name)
+
+	buf := new(bytes.Buffer)
+	l := cliutil.NewLogger(func(l *cliutil.Logger) {
+		l.Out = buf
+		l.NoColor = true
+	})
+	is.NoError(opts.Apply(l))
+	is.Equal(cliutil.LevelDebug, l.Level)
+
+	l.WithTag("app").Debug("\x1b[32mdebug\x1b[0m message")
+	is.NoError(l.Close())
+
+	bs, err := os.ReadFile(logFile)
+	is.NoError(err)
+	is.Contains(string(bs), " DEBUG   [app] debug message\n")
+
+	opts.LogLevel = "invalid"
+	is.Error(opts.Apply(l))
+}
@@ -0,0 +1,227 @@
+package cliutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted error on user press Ctrl+C when interactive reading
+var ErrInterrupted = errors.New("cliutil: interrupted")
+
+// Validator func for validate the user input
+type Validator func(input string) error
+
+// Prompter for read user input from the terminal.
+//
+// If the input is not a terminal, it will fallback to read lines from the input,
+// so it's easy to test by inject readers and writers.
+type Prompter struct {
+	// In input reader. default is os.Stdin
+	In io.Reader
+	// Out output writer. default is os.Stdout
+	Out io.Writer
+	// Interactive use the interactive mode(eg: arrow keys for select).
+	// default is auto detect by the In is a terminal.
+	Interactive bool
+
+	reader *bufio.Reader
+}
+
+// NewPrompter create, the interactive mode is auto detected.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{
+		In:          in,
+		Out:         out,
+		Interactive: isTerminal(in),
+	}
+}
+
+// std prompter for the package functions
+var std = NewPrompter(os.Stdin, os.Stdout)
+
+// check the reader or writer is a terminal. it is a var for mock on the tests.
+var isTerminal = func(v interface{}) bool {
+	f, ok := v.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// terminal fd of the input, returns -1 on it is not a terminal
+func (p *Prompter) termFd() int {
+	if f, ok := p.In.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return int(f.Fd())
+	}
+	return -1
+}
+
+// get the buffered reader of the In, create it on first use.
+// so the Prompter can be created without NewPrompter.
+func (p *Prompter) bufReader() *bufio.Reader {
+	if p.reader == nil {
+		if p.In == nil {
+			p.In = os.Stdin
+		}
+		p.reader = bufio.NewReader(p.In)
+	}
+	return p.reader
+}
+
+func (p *Prompter) printf(format string, args ...interface{}) {
+	if p.Out == nil {
+		p.Out = os.Stdout
+	}
+	_, _ = fmt.Fprintf(p.Out, format, args...)
+}
+
+// ReadLine read a line from the input. the tail "\r\n" will be trimmed.
+func (p *Prompter) ReadLine(question string) (string, error) {
+	if question != "" {
+		p.printf("%s", question)
+	}
+
+	line, err := p.bufReader().ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Input read user input with default value and validators.
+// will ask again on the validation failed.
+//
+// Usage:
+// 	name, err := p.Input("Your name", "inhere", func(s string) error {
+// 		if len(s) < 3 {
+// 			return errors.New("name is too short")
+// 		}
+// 		return nil
+// 	})
+func (p *Prompter) Input(question, defVal string, validators ...Validator) (string, error) {
+	prompt := question
+	if defVal != "" {
+		prompt += fmt.Sprintf(" [%s]", defVal)
+	}
+
+	for {
+		input, err := p.ReadLine(prompt + ": ")
+		if err != nil {
+			return "", err
+		}
+
+		if input = strings.TrimSpace(input); input == "" {
+			input = defVal
+		}
+
+		if err = validate(input, validators); err != nil {
+			p.printf("%s\n", err.Error())
+			continue
+		}
+		return input, nil
+	}
+}
+
+func validate(input string, validators []Validator) error {
+	for _, fn := range validators {
+		if err := fn(input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Password read user input without echo on the terminal.
+// if the input is not a terminal, will read a line.
+func (p *Prompter) Password(question string, validators ...Validator) (string, error) {
+	for {
+		var input string
+		if fd := p.termFd(); fd >= 0 {
+			p.printf("%s: ", question)
+			bs, err := term.ReadPassword(fd)
+			p.printf("\n")
+			if err != nil {
+				return "", err
+			}
+			input = string(bs)
+		} else {
+			line, err := p.ReadLine(question + ": ")
+			if err != nil {
+				return "", err
+			}
+			input = line
+		}
+
+		if err := validate(input, validators); err != nil {
+			p.printf("%s\n", err.Error())
+			continue
+		}
+		return input, nil
+	}
+}
+
+// Confirm ask user to answer yes or no. will ask again on invalid answer.
+//
+// Usage:
+// 	ok, err := p.Confirm("Are you sure", false)
+func (p *Prompter) Confirm(question string, defVal bool) (bool, error) {
+	opts := "y/N"
+	if defVal {
+		opts = "Y/n"
+	}
+
+	for {
+		input, err := p.ReadLine(fmt.Sprintf("%s [%s]: ", question, opts))
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "":
+			return defVal, nil
+		case "y", "yes", "true", "1":
+			return true, nil
+		case "n", "no", "false", "0":
+			return false, nil
+		}
+		p.printf("Please answer yes or no.\n")
+	}
+}
+
+//
+// ------------------ functions use os.Stdin and os.Stdout ------------------
+//
+
+// ReadLine read a line from os.Stdin
+func ReadLine(question string) (string, error) {
+	return std.ReadLine(question)
+}
+
+// ReadInput read user input with default value and validators
+func ReadInput(question, defVal string, validators ...Validator) (string, error) {
+	return std.Input(question, defVal, validators...)
+}
+
+// ReadPassword read user input without echo
+func ReadPassword(question string, validators ...Validator) (string, error) {
+	return std.Password(question, validators...)
+}
+
+// Confirm ask user to answer yes or no
+func Confirm(question string, defVal ...bool) bool {
+	ok, _ := std.Confirm(question, len(defVal) > 0 && defVal[0])
+	return ok
+}
+
+// SelectOne let user select one option, returns the option index
+func SelectOne(title string, options []string, defIdx int) (int, error) {
+	return std.Select(title, options, defIdx)
+}
+
+// MultiSelect let user select multi options, returns the option indexes
+func MultiSelect(title string, options []string, defIdxes []int) ([]int, error) {
+	return std.MultiSelect(title, options, defIdxes)
+}
@@ -0,0 +1,169 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/cliutil"
+)
+
+func newPrompter(input string) (*cliutil.Prompter, *bytes.Buffer) {
+	out := new(bytes.Buffer)
+	return cliutil.NewPrompter(strings.NewReader(input), out), out
+}
+
+func TestPrompter_Input(t *testing.T) {
+	is := assert.New(t)
+
+	p, out := newPrompter("\n")
+	is.False(p.Interactive)
+	name, err := p.Input("Your name", "inhere")
+	is.NoError(err)
+	is.Equal("inhere", name)
+	is.Equal("Your name [inhere]: ", out.String())
+
+	minLen := func(s string) error {
+		if len(s) < 3 {
+			return errors.New("name is too short")
+		}
+		return nil
+	}
+
+	p, out = newPrompter("ab\ntom\n")
+	name, err = p.Input("Your name", "", minLen)
+	is.NoError(err)
+	is.Equal("tom", name)
+	is.Contains(out.String(), "name is too short\n")
+
+	p, _ = newPrompter("ab")
+	_, err = p.Input("Your name", "", minLen)
+	is.Equal(io.EOF, err)
+
+	p, _ = newPrompter("secret\r\n")
+	pwd, err := p.Password("Password")
+	is.NoError(err)
+	is.Equal("secret", pwd)
+}
+
+func TestPrompter_Confirm(t *testing.T) {
+	is := assert.New(t)
+
+	p, _ := newPrompter("\nyes\nN\n")
+	ok, err := p.Confirm("Are you sure", true)
+	is.NoError(err)
+	is.True(ok)
+	ok, _ = p.Confirm("Are you sure", false)
+	is.True(ok)
+	ok, _ = p.Confirm("Are you sure", true)
+	is.False(ok)
+
+	p, out := newPrompter("what\ny\n")
+	ok, err = p.Confirm("Continue", false)
+	is.NoError(err)
+	is.True(ok)
+	is.Contains(out.String(), "Continue [y/N]: Please answer yes or no.\n")
+}
+
+func TestPrompter_Select(t *testing.T) {
+	is := assert.New(t)
+	cities := []string{"chengdu", "beijing", "shanghai"}
+
+	p, out := newPrompter("\n4\nBeijing\n3\n")
+	idx, err := p.Select("Your city", cities, 0)
+	is.NoError(err)
+	is.Equal(0, idx)
+	is.Contains(out.String(), "Your city\n  1) chengdu\n  2) beijing\n  3) shanghai\nEnter number [1]: ")
+
+	idx, err = p.Select("Your city", cities, 0)
+	is.NoError(err)
+	is.Equal(1, idx)
+	is.Contains(out.String(), `Invalid option "4"`)
+
+	idx, err = p.Select("Your city", cities, 0)
+	is.NoError(err)
+	is.Equal(2, idx)
+
+	_, err = p.Select("Your city", nil, 0)
+	is.Equal(cliutil.ErrNoOptions, err)
+
+	p, _ = newPrompter("\n3, 1,3\n")
+	idxes, err := p.MultiSelect("Your cities", cities, []int{1})
+	is.NoError(err)
+	is.Equal([]int{1}, idxes)
+	idxes, err = p.MultiSelect("Your cities", cities, nil)
+	is.NoError(err)
+	is.Equal([]int{0, 2}, idxes)
+}
+
+func TestPrompter_interactiveSelect(t *testing.T) {
+	is := assert.New(t)
+	cities := []string{"chengdu", "beijing", "shanghai"}
+
+	// down, down, up, enter
+	p, out := newPrompter("\x1b[B\x1b[Bk\r")
+	p.Interactive = true
+	idx, err := p.Select("Your city", cities, 0)
+	is.NoError(err)
+	is.Equal(1, idx)
+	is.Contains(out.String(), "> beijing")
+	is.True(strings.HasSuffix(out.String(), "Your city: beijing\r\n"))
+
+	// up to last, space, down, space, enter
+	p, _ = newPrompter("\x1b[A jj \r")
+	p.Interactive = true
+	idxes, err := p.MultiSelect("Your cities", cities, nil)
+	is.NoError(err)
+	is.Equal([]int{1, 2}, idxes)
+
+	p, _ = newPrompter("j\x03")
+	p.Interactive = true
+	_, err = p.Select("Your city", cities, 0)
+	is.Equal(cliutil.ErrInterrupted, err)
+
+	// the invalid default indexes are ignored
+	p, _ = newPrompter(" \r")
+	p.Interactive = true
+	idxes, err = p.MultiSelect("Your cities", cities, []int{5, -1})
+	is.NoError(err)
+	is.Equal([]int{0}, idxes)
+}
+
+func TestPrompter_invalidDefaults(t *testing.T) {
+	is := assert.New(t)
+	cities := []string{"chengdu", "beijing"}
+
+	p, out := newPrompter("\n")
+	idxes, err := p.MultiSelect("Your cities", cities, []int{5, 1, -1, 1})
+	is.NoError(err)
+	is.Equal([]int{1}, idxes)
+	is.Contains(out.String(), "[2]: ")
+
+	p, _ = newPrompter("\r")
+	p.Interactive = true
+	idxes, err = p.MultiSelect("Your cities", cities, []int{5, 1})
+	is.NoError(err)
+	is.Equal([]int{1}, idxes)
+}
+
+func TestPrompter_zeroValue(t *testing.T) {
+	is := assert.New(t)
+	out := new(bytes.Buffer)
+
+	p := &cliutil.Prompter{In: strings.NewReader("tom\n2\n"), Out: out}
+	name, err := p.Input("Your name", "")
+	is.NoError(err)
+	is.Equal("tom", name)
+
+	idx, err := p.Select("Your city", []string{"chengdu", "beijing"}, 0)
+	is.NoError(err)
+	is.Equal(1, idx)
+
+	p = &cliutil.Prompter{In: strings.NewReader("j\r"), Out: out, Interactive: true}
+	idx, err = p.Select("Your city", []string{"chengdu", "beijing"}, 0)
+	is.NoError(err)
+	is.Equal(1, idx)
+}
@@ -0,0 +1,282 @@
+package cliutil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// keys for interactive select
+const (
+	keyUnknown = iota
+	keyUp
+	keyDown
+	keyEnter
+	keySpace
+	keyInterrupt
+)
+
+// ErrNoOptions error on select without options
+var ErrNoOptions = errors.New("cliutil: the select options is empty")
+
+// Select let user select one option, returns the selected option index.
+//
+// On interactive mode, use the arrow keys(or j, k) to move and Enter to confirm.
+// otherwise, input the option number or option text.
+//
+// Usage:
+// 	idx, err := p.Select("Your city", []string{"chengdu", "beijing"}, 0)
+func (p *Prompter) Select(title string, options []string, defIdx int) (int, error) {
+	if len(options) == 0 {
+		return -1, ErrNoOptions
+	}
+
+	if defIdx < 0 || defIdx >= len(options) {
+		defIdx = 0
+	}
+
+	if p.Interactive {
+		selected, err := p.interactiveSelect(title, options, []int{defIdx}, false)
+		if err != nil {
+			return -1, err
+		}
+		return selected[0], nil
+	}
+
+	p.printOptions(title, options)
+	for {
+		input, err := p.ReadLine(fmt.Sprintf("Enter number [%d]: ", defIdx+1))
+		if err != nil {
+			return -1, err
+		}
+
+		if input = strings.TrimSpace(input); input == "" {
+			return defIdx, nil
+		}
+
+		if idx := findOption(options, input); idx >= 0 {
+			return idx, nil
+		}
+		p.printf("Invalid option %q, please try again.\n", input)
+	}
+}
+
+// MultiSelect let user select multi options, returns the selected option indexes.
+//
+// On interactive mode, use the arrow keys(or j, k) to move, Space to
+// toggle and Enter to confirm. otherwise, input the option numbers separated by comma.
+func (p *Prompter) MultiSelect(title string, options []string, defIdxes []int) ([]int, error) {
+	if len(options) == 0 {
+		return nil, ErrNoOptions
+	}
+
+	// remove the invalid default indexes
+	valid := make([]int, 0, len(defIdxes))
+	for _, idx := range defIdxes {
+		if idx >= 0 && idx < len(options) {
+			valid = append(valid, idx)
+		}
+	}
+	defIdxes = uniqueSorted(valid)
+
+	if p.Interactive {
+		return p.interactiveSelect(title, options, defIdxes, true)
+	}
+
+	defNums := make([]string, len(defIdxes))
+	for i, idx := range defIdxes {
+		defNums[i] = strconv.Itoa(idx + 1)
+	}
+
+	p.printOptions(title, options)
+	for {
+		input, err := p.ReadLine(fmt.Sprintf("Enter numbers separated by comma [%s]: ", strings.Join(defNums, ",")))
+		if err != nil {
+			return nil, err
+		}
+
+		if input = strings.TrimSpace(input); input == "" {
+			return defIdxes, nil
+		}
+
+		selected, invalid := make([]int, 0), ""
+		for _, item := range strings.Split(input, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+
+			idx := findOption(options, item)
+			if idx < 0 {
+				invalid = item
+				break
+			}
+			selected = append(selected, idx)
+		}
+
+		if invalid == "" {
+			return uniqueSorted(selected), nil
+		}
+		p.printf("Invalid option %q, please try again.\n", invalid)
+	}
+}
+
+func (p *Prompter) printOptions(title string, options []string) {
+	p.printf("%s\n", title)
+	for i, opt := range options {
+		p.printf("  %d) %s\n", i+1, opt)
+	}
+}
+
+// find option index by number or option text. returns -1 on not found.
+func findOption(options []string, input string) int {
+	if num, err := strconv.Atoi(input); err == nil {
+		if num > 0 && num <= len(options) {
+			return num - 1
+		}
+		return -1
+	}
+
+	for i, opt := range options {
+		if strings.EqualFold(opt, input) {
+			return i
+		}
+	}
+	return -1
+}
+
+func uniqueSorted(ints []int) []int {
+	sort.Ints(ints)
+
+	ret := ints[:0]
+	for i, v := range ints {
+		if i == 0 || v != ints[i-1] {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func (p *Prompter) readKey() (int, error) {
+	b, err := p.bufReader().ReadByte()
+	if err != nil {
+		return keyUnknown, err
+	}
+
+	switch b {
+	case '\r', '\n':
+		return keyEnter, nil
+	case ' ':
+		return keySpace, nil
+	case 'k':
+		return keyUp, nil
+	case 'j':
+		return keyDown, nil
+	case 3: // Ctrl+C
+		return keyInterrupt, nil
+	case 0x1b: // escape sequence. eg: "\x1b[A"
+		if next, _ := p.bufReader().ReadByte(); next != '[' && next != 'O' {
+			return keyUnknown, nil
+		}
+
+		switch code, _ := p.bufReader().ReadByte(); code {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		}
+	}
+	return keyUnknown, nil
+}
+
+func (p *Prompter) interactiveSelect(title string, options []string, defIdxes []int, multi bool) ([]int, error) {
+	if fd := p.termFd(); fd >= 0 {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+	}
+
+	checked := make(map[int]bool, len(defIdxes))
+	for _, idx := range defIdxes {
+		checked[idx] = true
+	}
+
+	// the defIdxes are validated by the caller
+	cursor := 0
+	if len(defIdxes) > 0 {
+		cursor = defIdxes[0]
+	}
+
+	// NOTICE: on raw mode, must use "\r\n" for new line
+	p.printf("%s\r\n", title)
+	render := func() {
+		for i, opt := range options {
+			mark := "  "
+			if i == cursor {
+				mark = "> "
+			}
+
+			if multi {
+				box := "[ ] "
+				if checked[i] {
+					box = "[x] "
+				}
+				mark += box
+			}
+			p.printf("\x1b[2K%s%s\r\n", mark, opt)
+		}
+	}
+
+	render()
+	for {
+		key, err := p.readKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case keyInterrupt:
+			return nil, ErrInterrupted
+		case keyUp:
+			cursor = (cursor - 1 + len(options)) % len(options)
+		case keyDown:
+			cursor = (cursor + 1) % len(options)
+		case keySpace:
+			if multi {
+				checked[cursor] = !checked[cursor]
+			}
+		case keyEnter:
+			var selected []int
+			if multi {
+				for idx := range checked {
+					if checked[idx] {
+						selected = append(selected, idx)
+					}
+				}
+				selected = uniqueSorted(selected)
+			} else {
+				selected = []int{cursor}
+			}
+
+			// clear the options, print the selected result
+			names := make([]string, len(selected))
+			for i, idx := range selected {
+				names[i] = options[idx]
+			}
+
+			p.printf("\x1b[%dA\x1b[J%s\r\n", len(options)+1, title+": "+strings.Join(names, ", "))
+			return selected, nil
+		default:
+			continue
+		}
+
+		// move cursor to the first option line and redraw
+		p.printf("\x1b[%dA", len(options))
+		render()
+	}
+}
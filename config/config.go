@@ -0,0 +1,460 @@
+// Package config load and merge the config data from JSON, JSONC, YAML, TOML and dotenv files,
+// overlay by the ENV vars, and read by the key path or bind to structs.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urionz/goutil/envutil"
+	"github.com/urionz/goutil/jsonutil"
+	"github.com/urionz/goutil/maputil"
+	"github.com/urionz/goutil/mathutil"
+	"github.com/urionz/goutil/strutil"
+)
+
+// Options for the config
+type Options struct {
+	// ParseEnv parse the ENV var in the string values on load. eg: "${DB_HOST|localhost}"
+	// default is true
+	ParseEnv bool
+}
+
+// Config the config data, which merged from multi sources
+type Config struct {
+	Options
+	lock sync.RWMutex
+	data map[string]interface{}
+	// the loaded files
+	files []string
+}
+
+// New create an empty config
+func New(fns ...func(opts *Options)) *Config {
+	c := &Config{
+		data:    make(map[string]interface{}),
+		Options: Options{ParseEnv: true},
+	}
+
+	for _, fn := range fns {
+		fn(&c.Options)
+	}
+	return c
+}
+
+// Load create the config and load the files
+//
+// Usage:
+// 	cfg, err := config.Load("config/app.yaml", "config/app.local.yaml")
+// 	cfg.LoadEnv("APP_")
+func Load(files ...string) (*Config, error) {
+	c := New()
+	return c, c.LoadFiles(files...)
+}
+
+//
+// ------------------ load data ------------------
+//
+
+// LoadFiles load and merge the files in order, the later file override the former.
+// the format is detected by the file ext. see FormatOf()
+func (c *Config) LoadFiles(files ...string) error {
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if err := c.LoadSource(FormatOf(file), src); err != nil {
+			return fmt.Errorf("%w, file: %s", err, file)
+		}
+
+		c.lock.Lock()
+		c.files = append(c.files, file)
+		c.lock.Unlock()
+	}
+	return nil
+}
+
+// LoadExists load and merge the files, skip the not exists files.
+func (c *Config) LoadExists(files ...string) error {
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil && os.IsNotExist(err) {
+			continue
+		}
+
+		if err := c.LoadFiles(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadSource decode the source by the format and merge to the config
+func (c *Config) LoadSource(format string, src []byte) error {
+	decode, ok := decoders[format]
+	if !ok {
+		return fmt.Errorf("config: unsupported format %q", format)
+	}
+
+	data, err := decode(src)
+	if err != nil {
+		return fmt.Errorf("config: decode %s source error: %w", format, err)
+	}
+
+	c.LoadData(data)
+	return nil
+}
+
+// LoadData deep merge the data to the config. the data will be copied, it is not changed by the config.
+func (c *Config) LoadData(data map[string]interface{}) {
+	data = normalizeMap(data)
+	if c.ParseEnv {
+		parseEnvValues(data)
+	}
+
+	c.lock.Lock()
+	mergeMap(c.data, data)
+	c.lock.Unlock()
+}
+
+// LoadedFiles get the loaded files
+func (c *Config) LoadedFiles() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return append([]string(nil), c.files...)
+}
+
+//
+// ------------------ read data ------------------
+//
+
+// Data get a copy of all the config data
+func (c *Config) Data() map[string]interface{} {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return normalizeMap(c.data)
+}
+
+// Get value by key path. eg: "db.host"
+// the map and slice value is a copy, change it will not affect the config.
+func (c *Config) Get(key string) (interface{}, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if key == "" {
+		return normalizeMap(c.data), true
+	}
+
+	val, ok := maputil.GetByPath(key, c.data)
+	return normalize(val), ok
+}
+
+// Has check the key path exists
+func (c *Config) Has(key string) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// Set value by key path. eg: "db.host"
+func (c *Config) Set(key string, val interface{}) {
+	val = normalize(val)
+	if c.ParseEnv {
+		val = parseEnvValue(val)
+	}
+
+	c.lock.Lock()
+	setByPath(c.data, strings.Split(key, "."), val)
+	c.lock.Unlock()
+}
+
+// String get string value by key path, returns the def on not found
+func (c *Config) String(key string, def ...string) string {
+	if val, ok := c.Get(key); ok {
+		if str, err := strutil.ToString(val); err == nil {
+			return str
+		}
+	}
+
+	if len(def) > 0 {
+		return def[0]
+	}
+	return ""
+}
+
+// Int get int value by key path, returns the def on not found or convert fail
+func (c *Config) Int(key string, def ...int) int {
+	if val, ok := c.Get(key); ok {
+		if iVal, err := mathutil.ToInt(val); err == nil {
+			return iVal
+		}
+	}
+
+	if len(def) > 0 {
+		return def[0]
+	}
+	return 0
+}
+
+// Int64 get int64 value by key path, returns the def on not found or convert fail
+func (c *Config) Int64(key string, def ...int64) int64 {
+	if val, ok := c.Get(key); ok {
+		if iVal, err := mathutil.ToInt64(val); err == nil {
+			return iVal
+		}
+	}
+
+	if len(def) > 0 {
+		return def[0]
+	}
+	return 0
+}
+
+// Float get float64 value by key path, returns the def on not found or convert fail
+func (c *Config) Float(key string, def ...float64) float64 {
+	if val, ok := c.Get(key); ok {
+		if fVal, err := mathutil.ToFloat(val); err == nil {
+			return fVal
+		}
+	}
+
+	if len(def) > 0 {
+		return def[0]
+	}
+	return 0
+}
+
+// Bool get bool value by key path, returns the def on not found or convert fail
+func (c *Config) Bool(key string, def ...bool) bool {
+	if val, ok := c.Get(key); ok {
+		switch tv := val.(type) {
+		case bool:
+			return tv
+		case string:
+			if bl, err := strutil.ToBool(tv); err == nil {
+				return bl
+			}
+		}
+	}
+
+	if len(def) > 0 {
+		return def[0]
+	}
+	return false
+}
+
+// Duration get time.Duration value by key path. the value can be "3s", "1h30m" or nanoseconds number.
+func (c *Config) Duration(key string, def ...time.Duration) time.Duration {
+	if val, ok := c.Get(key); ok {
+		if str, isStr := val.(string); isStr {
+			if dur, err := time.ParseDuration(str); err == nil {
+				return dur
+			}
+		} else if i64, err := mathutil.ToInt64(val); err == nil {
+			return time.Duration(i64)
+		}
+	}
+
+	if len(def) > 0 {
+		return def[0]
+	}
+	return 0
+}
+
+// Strings get string slice by key path. the string value will be split by ","
+func (c *Config) Strings(key string) (ss []string) {
+	val, ok := c.Get(key)
+	if !ok {
+		return
+	}
+
+	switch tv := val.(type) {
+	case string:
+		return strutil.ToSlice(tv)
+	case []interface{}:
+		for _, item := range tv {
+			ss = append(ss, strutil.MustString(item))
+		}
+	}
+	return
+}
+
+// StringMap get string map by key path
+func (c *Config) StringMap(key string) map[string]string {
+	val, ok := c.Get(key)
+	if !ok {
+		return nil
+	}
+
+	mp, ok := val.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	smp := make(map[string]string, len(mp))
+	for k, v := range mp {
+		smp[k] = strutil.MustString(v)
+	}
+	return smp
+}
+
+// Keys get the sorted top keys or the sub keys of the key path
+func (c *Config) Keys(key string) []string {
+	val, ok := c.Get(key)
+	if !ok {
+		return nil
+	}
+
+	keys := maputil.Keys(val)
+	sort.Strings(keys)
+	return keys
+}
+
+// Bind the data of the key path to the struct pointer by the json tags.
+// bind all the data on the key is empty.
+//
+// Usage:
+// 	dbConf := &DBConfig{}
+// 	err := cfg.Bind("db", dbConf)
+func (c *Config) Bind(key string, ptr interface{}) error {
+	val, ok := c.Get(key)
+	if !ok {
+		return fmt.Errorf("config: key %q not found", key)
+	}
+
+	bs, err := jsonutil.Encode(val)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonutil.Decode(bs, ptr); err != nil {
+		return fmt.Errorf("config: bind %q error: %w", key, err)
+	}
+	return nil
+}
+
+//
+// ------------------ helper functions ------------------
+//
+
+// deep merge the src map to dst map. the map values are merged, others are replaced.
+func mergeMap(dst, src map[string]interface{}) {
+	for key, sv := range src {
+		if smp, ok := sv.(map[string]interface{}); ok {
+			if dmp, ok := dst[key].(map[string]interface{}); ok {
+				mergeMap(dmp, smp)
+				continue
+			}
+		}
+		dst[key] = sv
+	}
+}
+
+// set the value by the path keys, create the sub maps on not exists
+func setByPath(mp map[string]interface{}, keys []string, val interface{}) {
+	last := len(keys) - 1
+	for _, key := range keys[:last] {
+		sub, ok := mp[key].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			mp[key] = sub
+		}
+		mp = sub
+	}
+	mp[keys[last]] = val
+}
+
+func normalizeMap(mp map[string]interface{}) map[string]interface{} {
+	cp := make(map[string]interface{}, len(mp))
+	for key, val := range mp {
+		cp[key] = normalize(val)
+	}
+	return cp
+}
+
+// normalize the decoded values, make the maps are map[string]interface{},
+// and the slices are []interface{}. the maps and slices are always copied.
+func normalize(val interface{}) interface{} {
+	switch tv := val.(type) {
+	case map[string]interface{}:
+		return normalizeMap(tv)
+	case map[interface{}]interface{}:
+		mp := make(map[string]interface{}, len(tv))
+		for k, v := range tv {
+			mp[fmt.Sprint(k)] = normalize(v)
+		}
+		return mp
+	case map[string]string:
+		mp := make(map[string]interface{}, len(tv))
+		for k, v := range tv {
+			mp[k] = v
+		}
+		return mp
+	case []interface{}:
+		ls := make([]interface{}, len(tv))
+		for i, v := range tv {
+			ls[i] = normalize(v)
+		}
+		return ls
+	case []map[string]interface{}: // array tables of TOML
+		ls := make([]interface{}, len(tv))
+		for i, v := range tv {
+			ls[i] = normalizeMap(v)
+		}
+		return ls
+	case []string:
+		ls := make([]interface{}, len(tv))
+		for i, v := range tv {
+			ls[i] = v
+		}
+		return ls
+	case nil, string, []byte:
+		return val
+	}
+
+	// other slices and maps. eg: []int, map[string]int
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		ls := make([]interface{}, rv.Len())
+		for i := range ls {
+			ls[i] = normalize(rv.Index(i).Interface())
+		}
+		return ls
+	case reflect.Map:
+		mp := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			mp[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value().Interface())
+		}
+		return mp
+	}
+	return val
+}
+
+func parseEnvValues(mp map[string]interface{}) {
+	for key, val := range mp {
+		mp[key] = parseEnvValue(val)
+	}
+}
+
+// parse the ENV vars in the string values. eg: "${DB_HOST|localhost}"
+func parseEnvValue(val interface{}) interface{} {
+	switch tv := val.(type) {
+	case string:
+		return envutil.ParseEnvValue(tv)
+	case map[string]interface{}:
+		parseEnvValues(tv)
+	case []interface{}:
+		for i, v := range tv {
+			tv[i] = parseEnvValue(v)
+		}
+	}
+	return val
+}
@@ -0,0 +1,235 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/config"
+)
+
+var allFiles = []string{
+	"testdata/app.json",
+	"testdata/app.yaml",
+	"testdata/app.toml",
+	"testdata/app.jsonc",
+	"testdata/.env",
+}
+
+func TestLoad(t *testing.T) {
+	is := assert.New(t)
+
+	t.Setenv("CFG_TEST_DB_HOST", "")
+	cfg, err := config.Load(allFiles...)
+	is.NoError(err)
+	is.Equal(allFiles, cfg.LoadedFiles())
+
+	// json
+	is.Equal("app", cfg.String("name"))
+	is.Equal("localhost", cfg.String("db.host"))
+	is.Equal(10, cfg.Int("db.max_conn"))
+	is.Equal([]string{"a", "b"}, cfg.Strings("tags"))
+	// yaml override json, and deep merged
+	is.True(cfg.Bool("debug"))
+	is.Equal("root", cfg.String("db.user"))
+	is.Equal(map[string]string{"charset": "utf8"}, cfg.StringMap("db.options"))
+	is.Equal(3*time.Second, cfg.Duration("timeout"))
+	// toml
+	is.Equal(9090, cfg.Int("port"))
+	is.Equal(int64(3307), cfg.Int64("db.port"))
+	is.Equal([]string{"host", "max_conn", "options", "password", "port", "user"}, cfg.Keys("db"))
+	// jsonc
+	is.Equal("redis", cfg.String("cache.driver"))
+	is.Equal(60.0, cfg.Float("cache.ttl"))
+	// dotenv
+	is.Equal("dev", cfg.String("APP_ENV"))
+	is.Equal("p@ss # word", cfg.String("db.password"))
+	is.Equal(`single \n quoted`, cfg.String("SINGLE"))
+	is.Equal("value", cfg.String("NOTE"))
+
+	// default values
+	is.False(cfg.Has("not-exists"))
+	is.Equal("def", cfg.String("not-exists", "def"))
+	is.Equal(2, cfg.Int("name", 2))
+	is.Equal(int64(2), cfg.Int64("not-exists", 2))
+	is.Equal(1.5, cfg.Float("not-exists", 1.5))
+	is.True(cfg.Bool("not-exists", true))
+	is.Equal(time.Minute, cfg.Duration("not-exists", time.Minute))
+	is.Nil(cfg.Strings("not-exists"))
+	is.Nil(cfg.StringMap("name"))
+	is.Nil(cfg.Keys("not-exists"))
+
+	_, err = config.Load("testdata/not-exists.json")
+	is.Error(err)
+
+	cfg = config.New()
+	is.NoError(cfg.LoadExists("testdata/not-exists.json", "testdata/app.toml"))
+	is.Equal([]string{"testdata/app.toml"}, cfg.LoadedFiles())
+
+	// returns a copy
+	cfg.LoadedFiles()[0] = "changed"
+	is.Equal([]string{"testdata/app.toml"}, cfg.LoadedFiles())
+}
+
+func TestConfig_parseEnv(t *testing.T) {
+	is := assert.New(t)
+
+	t.Setenv("CFG_TEST_DB_HOST", "10.0.0.3")
+	cfg, err := config.Load("testdata/app.json")
+	is.NoError(err)
+	is.Equal("10.0.0.3", cfg.String("db.host"))
+
+	cfg.Set("db.name", "${CFG_TEST_DB_NAME|test}")
+	is.Equal("test", cfg.String("db.name"))
+
+	cfg = config.New(func(opts *config.Options) {
+		opts.ParseEnv = false
+	})
+	is.NoError(cfg.LoadFiles("testdata/app.json"))
+	is.Equal("${CFG_TEST_DB_HOST|localhost}", cfg.String("db.host"))
+}
+
+func TestConfig_LoadSource(t *testing.T) {
+	is := assert.New(t)
+
+	cfg := config.New()
+	is.NoError(cfg.LoadSource(config.YAML, []byte("a:\n  1: one\nb: [1, 2]")))
+	is.Equal("one", cfg.String("a.1"))
+	is.Equal([]string{"1", "2"}, cfg.Strings("b"))
+
+	is.Error(cfg.LoadSource("ini", []byte("a=b")))
+	is.Error(cfg.LoadSource(config.JSON, []byte("{invalid")))
+	is.Error(cfg.LoadSource(config.Dotenv, []byte("invalid")))
+	is.Error(cfg.LoadSource(config.Dotenv, []byte(`KEY="unclosed`)))
+
+	config.AddDecoder("lines", func(src []byte) (map[string]interface{}, error) {
+		return map[string]interface{}{"lines": string(src)}, nil
+	})
+	is.NoError(cfg.LoadSource("lines", []byte("a\nb")))
+	is.Equal("a\nb", cfg.String("lines"))
+
+	is.Equal(config.YAML, config.FormatOf("path/to/app.yml"))
+	is.Equal(config.Dotenv, config.FormatOf(".env"))
+	is.Equal(config.Dotenv, config.FormatOf("path/to/.env.local"))
+	is.Equal(config.Dotenv, config.FormatOf("app.env"))
+	is.Equal("local", config.FormatOf("app.local"))
+	is.Equal(config.JSONC, config.FormatOf("app.JSONC"))
+}
+
+func TestConfig_LoadEnv(t *testing.T) {
+	is := assert.New(t)
+
+	cfg := config.New()
+	cfg.LoadData(map[string]interface{}{
+		"debug": false,
+		"tags":  []string{"a"},
+		"db": map[string]interface{}{
+			"port":     3306,
+			"max_conn": 10.0,
+		},
+	})
+
+	t.Setenv("CFG_TEST_DEBUG", "true")
+	t.Setenv("CFG_TEST_TAGS", "b,c")
+	t.Setenv("CFG_TEST_DB_PORT", "3307")
+	t.Setenv("CFG_TEST_DB_MAX_CONN", "20")
+	t.Setenv("CFG_TEST_DB_USER_NAME", "root")
+	t.Setenv("CFG_TEST_NEW_KEY", "val")
+	t.Setenv("CFG_TEST_", "ignored")
+	cfg.LoadEnv("CFG_TEST_")
+
+	is.Equal(true, cfg.Data()["debug"])
+	is.Equal([]string{"b", "c"}, cfg.Strings("tags"))
+	is.Equal(3307, cfg.Int("db.port"))
+	is.Equal(20.0, cfg.Float("db.max_conn"))
+	is.Equal("root", cfg.String("db.user.name"))
+	is.Equal("val", cfg.String("new.key"))
+	is.Equal([]string{"db", "debug", "new", "tags"}, cfg.Keys(""))
+
+	// keep the string on convert fail
+	t.Setenv("CFG_TEST_DB_PORT", "invalid")
+	cfg.LoadEnv("CFG_TEST_")
+	is.Equal("invalid", cfg.String("db.port"))
+
+	// skip the ENV vars which will replace the exists data
+	cfg = config.New()
+	cfg.LoadData(map[string]interface{}{
+		"name": "app",
+		"db":   map[string]interface{}{"host": "localhost"},
+	})
+
+	t.Setenv("CFG_SKIP_NAME_FIRST", "x")
+	t.Setenv("CFG_SKIP_DB", "x")
+	cfg.LoadEnv("CFG_SKIP_")
+	is.Equal("app", cfg.String("name"))
+	is.Equal("localhost", cfg.String("db.host"))
+}
+
+func TestConfig_copyData(t *testing.T) {
+	is := assert.New(t)
+
+	tags := []interface{}{"a"}
+	db := map[string]interface{}{"host": "${CFG_TEST_DB_HOST|localhost}", "ports": []int{3306}}
+	data := map[string]interface{}{"db": db, "tags": tags}
+
+	cfg := config.New()
+	cfg.LoadData(data)
+	cfg.Set("list", tags)
+	is.Equal("${CFG_TEST_DB_HOST|localhost}", db["host"])
+
+	// the caller data is not stored by reference
+	db["host"] = "changed"
+	tags[0] = "changed"
+	db["ports"].([]int)[0] = 1
+	is.Equal("localhost", cfg.String("db.host"))
+	is.Equal([]string{"a"}, cfg.Strings("tags"))
+	is.Equal([]string{"a"}, cfg.Strings("list"))
+	ports, _ := cfg.Get("db.ports")
+	is.Equal([]interface{}{3306}, ports)
+
+	// the returned data is a copy
+	cfg.Data()["name"] = "app"
+	cfg.Data()["db"].(map[string]interface{})["host"] = "changed"
+	val, ok := cfg.Get("tags")
+	is.True(ok)
+	val.([]interface{})[0] = "changed"
+
+	is.False(cfg.Has("name"))
+	is.Equal("localhost", cfg.String("db.host"))
+	is.Equal([]string{"a"}, cfg.Strings("tags"))
+}
+
+func TestConfig_Bind(t *testing.T) {
+	is := assert.New(t)
+
+	type dbConfig struct {
+		Host    string            `json:"host"`
+		Port    int               `json:"port"`
+		MaxConn int               `json:"max_conn"`
+		Options map[string]string `json:"options"`
+	}
+
+	t.Setenv("CFG_TEST_DB_HOST", "")
+	cfg, err := config.Load(allFiles[:3]...)
+	is.NoError(err)
+
+	db := &dbConfig{}
+	is.NoError(cfg.Bind("db", db))
+	is.Equal(dbConfig{"localhost", 3307, 10, map[string]string{"charset": "utf8"}}, *db)
+
+	var app struct {
+		Name    string `json:"name"`
+		Servers []struct {
+			Host string `json:"host"`
+		} `json:"servers"`
+		Clients []map[string]string `json:"clients"`
+	}
+	is.NoError(cfg.Bind("", &app))
+	is.Equal("app", app.Name)
+	is.Len(app.Servers, 2)
+	is.Equal("10.0.0.2", app.Servers[1].Host)
+	is.Equal("web", app.Clients[0]["name"])
+
+	is.Error(cfg.Bind("not-exists", db))
+	is.Error(cfg.Bind("name", db))
+}
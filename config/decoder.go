@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urionz/goutil/jsonutil"
+	"gopkg.in/yaml.v3"
+)
+
+// supported config formats
+const (
+	JSON   = "json"
+	JSONC  = "jsonc"
+	JSON5  = "json5"
+	YAML   = "yaml"
+	TOML   = "toml"
+	Dotenv = "env"
+)
+
+// Decoder decode the config source to map data
+type Decoder func(src []byte) (map[string]interface{}, error)
+
+var decoders = map[string]Decoder{
+	JSON:   decodeJSON,
+	JSONC:  decodeJSON5,
+	JSON5:  decodeJSON5,
+	YAML:   decodeYAML,
+	TOML:   decodeTOML,
+	Dotenv: decodeDotenv,
+}
+
+// the file ext aliases of the formats
+var formatAliases = map[string]string{
+	"yml": YAML,
+}
+
+// AddDecoder add or override the decoder for the format.
+//
+// NOTICE: it is not concurrent safe, please call it on init.
+//
+// Usage:
+// 	config.AddDecoder("ini", func(src []byte) (map[string]interface{}, error) {
+// 		// ...
+// 	})
+func AddDecoder(format string, fn Decoder) {
+	decoders[format] = fn
+}
+
+// FormatOf get the config format by the file ext. eg: "app.yml" => "yaml"
+// the file name start with ".env" is dotenv format. eg: ".env", ".env.local"
+func FormatOf(file string) string {
+	if name := filepath.Base(file); name == ".env" || strings.HasPrefix(name, ".env.") {
+		return Dotenv
+	}
+
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(file), "."))
+	if alias, ok := formatAliases[format]; ok {
+		return alias
+	}
+	return format
+}
+
+func decodeJSON(src []byte) (mp map[string]interface{}, err error) {
+	err = jsonutil.Decode(src, &mp)
+	return
+}
+
+func decodeJSON5(src []byte) (mp map[string]interface{}, err error) {
+	err = jsonutil.DecodeJSON5(src, &mp)
+	return
+}
+
+func decodeYAML(src []byte) (mp map[string]interface{}, err error) {
+	err = yaml.Unmarshal(src, &mp)
+	return
+}
+
+func decodeTOML(src []byte) (mp map[string]interface{}, err error) {
+	err = toml.Unmarshal(src, &mp)
+	return
+}
+
+// decode the dotenv contents. the key with "." is nested. eg: "db.host=localhost"
+//
+// 	# comments
+// 	export NAME=value
+// 	QUOTED="line1\nline2"
+// 	SINGLE='single quoted'
+func decodeDotenv(src []byte) (map[string]interface{}, error) {
+	mp := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+
+	var lineNo int
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		pos := strings.IndexByte(line, '=')
+		if pos < 1 {
+			return nil, fmt.Errorf("config: invalid dotenv line %d: %q", lineNo, line)
+		}
+
+		key := strings.TrimSpace(line[:pos])
+		val, err := dotenvValue(strings.TrimSpace(line[pos+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("config: invalid dotenv value at line %d: %w", lineNo, err)
+		}
+
+		setByPath(mp, strings.Split(key, "."), val)
+	}
+	return mp, scanner.Err()
+}
+
+func dotenvValue(val string) (string, error) {
+	if val == "" {
+		return val, nil
+	}
+
+	switch val[0] {
+	case '"':
+		end := strings.LastIndexByte(val, '"')
+		if end < 1 {
+			return "", fmt.Errorf("unclosed quote in %s", val)
+		}
+		return strconv.Unquote(val[:end+1])
+	case '\'':
+		end := strings.LastIndexByte(val, '\'')
+		if end < 1 {
+			return "", fmt.Errorf("unclosed quote in %s", val)
+		}
+		return val[1:end], nil
+	}
+
+	// strip the inline comment. eg: "value # comment"
+	if pos := strings.Index(val, " #"); pos > 0 {
+		val = strings.TrimSpace(val[:pos])
+	}
+	return val, nil
+}
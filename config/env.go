@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urionz/goutil/strutil"
+)
+
+// LoadEnv overlay the ENV vars with the prefix to the config.
+// the name is matched by the exists keys first, the others are split by "_".
+// the value is converted to the type of the exists value, eg: int, bool, slice
+//
+// the ENV var will be skipped on it can not be set without replace the exists data.
+// eg: APP_DB_HOST is skipped on "db" is a string, APP_DB is skipped on "db" is a map.
+//
+// Usage:
+// 	cfg.LoadEnv("APP_")
+// 	// APP_DB_HOST=localhost   => db.host
+// 	// APP_DB_MAX_CONN=10      => db.max_conn, if "db.max_conn" exists
+// 	// APP_DEBUG=true          => debug
+func (c *Config) LoadEnv(prefix string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, env := range os.Environ() {
+		pos := strings.IndexByte(env, '=')
+		if pos < 1 {
+			continue
+		}
+
+		name := env[:pos]
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+
+		keys := envKeys(c.data, strings.ToLower(name[len(prefix):]))
+		old, ok := getByKeys(c.data, keys)
+		if _, isMap := old.(map[string]interface{}); isMap || !ok && !canSetByKeys(c.data, keys) {
+			continue
+		}
+		setByPath(c.data, keys, convertEnv(old, env[pos+1:]))
+	}
+}
+
+// resolve the key path by the ENV name and the exists keys. the longer key is matched first
+func envKeys(mp map[string]interface{}, name string) []string {
+	keys := make([]string, 0, len(mp))
+	for key := range mp {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return len(keys[i]) > len(keys[j])
+	})
+
+	for _, key := range keys {
+		lk := strings.ToLower(key)
+		if name == lk {
+			return []string{key}
+		}
+
+		if sub, ok := mp[key].(map[string]interface{}); ok && strings.HasPrefix(name, lk+"_") {
+			return append([]string{key}, envKeys(sub, name[len(lk)+1:])...)
+		}
+	}
+	return strings.Split(name, "_")
+}
+
+func getByKeys(mp map[string]interface{}, keys []string) (val interface{}, ok bool) {
+	for i, key := range keys {
+		if val, ok = mp[key]; !ok || i == len(keys)-1 {
+			return
+		}
+
+		if mp, ok = val.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return
+}
+
+// check the parents of the keys are maps or not exists, so the value can be set.
+func canSetByKeys(mp map[string]interface{}, keys []string) bool {
+	for _, key := range keys[:len(keys)-1] {
+		val, ok := mp[key]
+		if !ok {
+			return true
+		}
+
+		if mp, ok = val.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// convert the ENV value to the type of the old value
+func convertEnv(old interface{}, val string) interface{} {
+	var err error
+	var newVal interface{}
+
+	switch old.(type) {
+	case bool:
+		newVal, err = strutil.ToBool(val)
+	case int:
+		newVal, err = strconv.Atoi(val)
+	case int64:
+		newVal, err = strconv.ParseInt(val, 10, 64)
+	case float64:
+		newVal, err = strconv.ParseFloat(val, 64)
+	case []interface{}:
+		ls := make([]interface{}, 0)
+		for _, s := range strutil.ToSlice(val) {
+			ls = append(ls, s)
+		}
+		return ls
+	default:
+		return val
+	}
+
+	if err != nil {
+		return val
+	}
+	return newVal
+}
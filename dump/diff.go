@@ -0,0 +1,322 @@
+package dump
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+
+	"github.com/urionz/color"
+)
+
+// DiffKind kind of the difference
+type DiffKind byte
+
+// difference kinds
+const (
+	DiffAdded   DiffKind = '+'
+	DiffRemoved DiffKind = '-'
+	DiffChanged DiffKind = '~'
+)
+
+// DiffItem one difference between two values
+type DiffItem struct {
+	Kind DiffKind
+	// Path of the value. eg: `.Users[0].Tags["name"]`, empty is the root value.
+	Path string
+	// Old value string, is empty on Kind is DiffAdded
+	Old string
+	// New value string, is empty on Kind is DiffRemoved
+	New string
+}
+
+// String of the diff item. eg: `~ .Name: string("a") => string("b")`
+func (di *DiffItem) String() string {
+	path := di.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	switch di.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %s", path, di.New)
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %s", path, di.Old)
+	}
+	return fmt.Sprintf("~ %s: %s => %s", path, di.Old, di.New)
+}
+
+// Diff print the differences of two values by std dumper
+func Diff(a, b interface{}) {
+	std.Diff(a, b)
+}
+
+// Diffs get the differences of two values by std dumper options
+func Diffs(a, b interface{}) []*DiffItem {
+	return std.Diffs(a, b)
+}
+
+// Diff print the differences between a and b. a is the expected, b is the actual.
+//
+// Usage:
+// 	dump.Diff(expected, actual)
+//
+// Output:
+// 	~ .Name: string("inhere") => string("tom")
+// 	- .Tags[2]: string("go")
+// 	+ .Meta["age"]: int(23)
+func (d *Dumper) Diff(a, b interface{}) {
+	d.diff(d.Output, a, b)
+}
+
+// Fdiff print the differences between a and b to io.Writer
+func (d *Dumper) Fdiff(w io.Writer, a, b interface{}) {
+	d.diff(w, a, b)
+}
+
+// Diffs get the differences between a and b.
+// will compare the values as a whole on the depth > MaxDepth.
+func (d *Dumper) Diffs(a, b interface{}) []*DiffItem {
+	df := &differ{opts: d.Options, maxDepth: d.MaxDepth, visited: make(map[[2]uintptr]bool)}
+	df.walk("", reflect.ValueOf(a), reflect.ValueOf(b), 0)
+	return df.items
+}
+
+// styles for the diff kinds
+var diffColors = map[DiffKind]color.Color{
+	DiffAdded:   color.FgGreen,
+	DiffRemoved: color.FgRed,
+	DiffChanged: color.FgYellow,
+}
+
+func (d *Dumper) diff(w io.Writer, a, b interface{}) {
+	// show print position
+	if d.ShowFlag != Fnopos {
+		if pc, file, line, ok := runtime.Caller(d.Skip); ok {
+			out := d.Output
+			d.Output = w
+			d.printCaller(pc, file, line)
+			d.Output = out
+		}
+	}
+
+	items := d.Diffs(a, b)
+	if len(items) == 0 {
+		mustFprint(w, "no differences\n")
+		return
+	}
+	d.printDiffs(w, items)
+}
+
+func (d *Dumper) printDiffs(w io.Writer, items []*DiffItem) {
+	for _, item := range items {
+		line := item.String()
+		if !d.NoColor {
+			line = color.RenderString(diffColors[item.Kind].Code(), line)
+		}
+		mustFprint(w, line, "\n")
+	}
+}
+
+type differ struct {
+	// opts for format the values, skip and mask the fields
+	opts     Options
+	maxDepth int
+	items    []*DiffItem
+	// visited pointer pairs, for avoid infinite loop on cycle references
+	visited map[[2]uintptr]bool
+}
+
+func (df *differ) add(kind DiffKind, path string, a, b reflect.Value) {
+	item := &DiffItem{Kind: kind, Path: path}
+	if kind != DiffAdded {
+		item.Old = df.format(a)
+	}
+	if kind != DiffRemoved {
+		item.New = df.format(b)
+	}
+	df.items = append(df.items, item)
+}
+
+// add the item of the masked value
+func (df *differ) addMasked(kind DiffKind, path string) {
+	item := &DiffItem{Kind: kind, Path: path}
+	if kind != DiffAdded {
+		item.Old = df.opts.maskText()
+	}
+	if kind != DiffRemoved {
+		item.New = df.opts.maskText()
+	}
+	df.items = append(df.items, item)
+}
+
+// check two values is equal, compare all the nested values.
+func (df *differ) equal(a, b reflect.Value) bool {
+	sub := &differ{opts: df.opts, maxDepth: -1, visited: df.visited}
+	sub.walk("", a, b, 0)
+	return len(sub.items) == 0
+}
+
+func (df *differ) walk(path string, a, b reflect.Value, depth int) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			df.add(DiffChanged, path, a, b)
+		}
+		return
+	}
+
+	if a.Type() != b.Type() {
+		df.add(DiffChanged, path, a, b)
+		return
+	}
+
+	// compare the formatted values. eg: time.Time, big.Int
+	if as, ok := df.opts.formatValue(a); ok {
+		if bs, _ := df.opts.formatValue(b); as != bs {
+			df.add(DiffChanged, path, a, b)
+		}
+		return
+	}
+
+	// compare as a whole on the depth is too deep
+	if df.maxDepth >= 0 && depth > df.maxDepth {
+		if !df.equal(a, b) {
+			df.add(DiffChanged, path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				df.add(DiffChanged, path, a, b)
+			}
+			return
+		}
+
+		if a.Kind() == reflect.Ptr {
+			key := [2]uintptr{a.Pointer(), b.Pointer()}
+			if key[0] == key[1] || df.visited[key] {
+				return
+			}
+			df.visited[key] = true
+		}
+		df.walk(path, a.Elem(), b.Elem(), depth)
+	case reflect.Struct:
+		rt := a.Type()
+		for i := 0; i < a.NumField(); i++ {
+			sf := rt.Field(i)
+			if df.opts.skipField(sf) {
+				continue
+			}
+
+			subPath := path + "." + sf.Name
+			if df.opts.maskField(sf) {
+				if !df.equal(a.Field(i), b.Field(i)) {
+					df.addMasked(DiffChanged, subPath)
+				}
+				continue
+			}
+			df.walk(subPath, a.Field(i), b.Field(i), depth+1)
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(a, b) {
+			subPath := fmt.Sprintf("%s[%#v]", path, key)
+			av, bv := a.MapIndex(key), b.MapIndex(key)
+			masked := df.opts.maskKey(key)
+
+			switch {
+			case !bv.IsValid() && masked:
+				df.addMasked(DiffRemoved, subPath)
+			case !bv.IsValid():
+				df.add(DiffRemoved, subPath, av, bv)
+			case !av.IsValid() && masked:
+				df.addMasked(DiffAdded, subPath)
+			case !av.IsValid():
+				df.add(DiffAdded, subPath, av, bv)
+			case masked:
+				if !df.equal(av, bv) {
+					df.addMasked(DiffChanged, subPath)
+				}
+			default:
+				df.walk(subPath, av, bv, depth+1)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		aLen, bLen := a.Len(), b.Len()
+		for i := 0; i < aLen || i < bLen; i++ {
+			subPath := fmt.Sprintf("%s[%d]", path, i)
+
+			switch {
+			case i >= bLen:
+				df.add(DiffRemoved, subPath, a.Index(i), reflect.Value{})
+			case i >= aLen:
+				df.add(DiffAdded, subPath, reflect.Value{}, b.Index(i))
+			default:
+				df.walk(subPath, a.Index(i), b.Index(i), depth+1)
+			}
+		}
+	default:
+		if !scalarEqual(a, b) {
+			df.add(DiffChanged, path, a, b)
+		}
+	}
+}
+
+// merge and sort the map keys of a and b
+func sortedKeys(a, b reflect.Value) []reflect.Value {
+	keys := a.MapKeys()
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+	return keys
+}
+
+func scalarEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return false
+}
+
+// format value to single line string by the compact renderer. eg: `string("abc")`, `int(23)`
+func (df *differ) format(rv reflect.Value) string {
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Interface {
+		return "<nil>"
+	}
+
+	st := newRenderState(df.opts)
+	st.compact(addressable(rv), 0)
+
+	// the compact output of the struct and pointer contains the type name
+	if kind := rv.Kind(); kind == reflect.Struct || kind == reflect.Ptr && !rv.IsNil() {
+		if _, formatted := df.opts.formatValue(rv); !formatted {
+			return st.buf.String()
+		}
+	}
+	return rv.Type().String() + "(" + st.buf.String() + ")"
+}
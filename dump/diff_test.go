@@ -0,0 +1,156 @@
+package dump
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffUser struct {
+	Name  string
+	Tags  []string
+	Meta  map[string]interface{}
+	Child *diffUser
+	age   int
+}
+
+func TestDumper_Diffs(t *testing.T) {
+	is := assert.New(t)
+
+	a := &diffUser{
+		Name: "inhere",
+		Tags: []string{"php", "go", "js"},
+		Meta: map[string]interface{}{"city": "chengdu", "level": 2},
+		age:  22,
+	}
+	b := &diffUser{
+		Name:  "tom",
+		Tags:  []string{"php", "go"},
+		Meta:  map[string]interface{}{"city": "chengdu", "level": 3, "score": 90},
+		Child: &diffUser{Name: "kid"},
+		age:   23,
+	}
+
+	d := NewDumper(new(bytes.Buffer), 2)
+	items := d.Diffs(a, b)
+
+	var lines []string
+	for _, item := range items {
+		lines = append(lines, item.String())
+	}
+
+	is.Equal([]string{
+		`~ .Name: string("inhere") => string("tom")`,
+		`- .Tags[2]: string("js")`,
+		`~ .Meta["level"]: int(2) => int(3)`,
+		`+ .Meta["score"]: int(90)`,
+		`~ .Child: *dump.diffUser(nil) => &dump.diffUser{Name:"kid", Tags:nil, Meta:nil, Child:nil, age:0}`,
+		`~ .age: int(22) => int(23)`,
+	}, lines)
+
+	is.Empty(d.Diffs(a, a))
+	is.Empty(d.Diffs([]int{1, 2}, []int{1, 2}))
+	is.Len(d.Diffs(nil, 1), 1)
+	is.Equal(`~ (root): int(1) => string("1")`, d.Diffs(1, "1")[0].String())
+}
+
+func TestDumper_Diffs_maxDepth(t *testing.T) {
+	is := assert.New(t)
+
+	a := map[string]interface{}{"a": map[string]interface{}{"b": map[string]int{"c": 1}}}
+	b := map[string]interface{}{"a": map[string]interface{}{"b": map[string]int{"c": 2}}}
+
+	d := NewDumper(new(bytes.Buffer), 2)
+	items := d.Diffs(a, b)
+	is.Len(items, 1)
+	is.Equal(`["a"]["b"]["c"]`, items[0].Path)
+
+	d.MaxDepth = 1
+	items = d.Diffs(a, b)
+	is.Len(items, 1)
+	is.Equal(`["a"]["b"]`, items[0].Path)
+	is.Equal(`map[string]int({"c":2})`, items[0].New)
+}
+
+func TestDumper_Diffs_options(t *testing.T) {
+	is := assert.New(t)
+
+	type account struct {
+		Name     string
+		Password string
+		Created  time.Time
+		Note     string `dump:"-"`
+		Data     []int
+	}
+
+	tm := time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)
+	a := account{Name: "inhere", Password: "123", Created: tm, Note: "a", Data: []int{1, 2, 3}}
+	b := account{Name: "inhere", Password: "456", Created: tm.Add(time.Hour), Note: "b", Data: []int{1, 2, 3, 4}}
+
+	d := NewDumper(new(bytes.Buffer), 2)
+	d.MaskFields = []string{"password", "token"}
+	d.MaxElements = 2
+
+	var lines []string
+	for _, item := range d.Diffs(a, b) {
+		lines = append(lines, item.String())
+	}
+	is.Equal([]string{
+		`~ .Password: ****** => ******`,
+		`~ .Created: time.Time(2022-01-02T15:04:05Z) => time.Time(2022-01-02T16:04:05Z)`,
+		`+ .Data[3]: int(4)`,
+	}, lines)
+
+	is.Empty(d.Diffs(account{Created: tm}, account{Created: tm.In(time.Local)}))
+
+	// masked map key and limited value
+	items := d.Diffs(map[string]interface{}{"token": "a"}, map[string]interface{}{"token": "b", "list": []int{1, 2, 3}})
+	is.Len(items, 2)
+	is.Equal(`+ ["list"]: []int([1, 2, ... 1 more])`, items[0].String())
+	is.Equal(`~ ["token"]: ****** => ******`, items[1].String())
+
+	items = d.Diffs(map[string]string{}, map[string]string{"token": "a"})
+	is.Equal(`+ ["token"]: ******`, items[0].String())
+}
+
+func TestDumper_Diffs_keysOrder(t *testing.T) {
+	a := map[interface{}]int{10: 1, 9: 1, "b": 1, "a": 1}
+	b := map[interface{}]int{}
+
+	var paths []string
+	for _, item := range NewDumper(new(bytes.Buffer), 2).Diffs(a, b) {
+		paths = append(paths, item.Path)
+	}
+	assert.Equal(t, []string{"[9]", "[10]", `["a"]`, `["b"]`}, paths)
+}
+
+func TestDumper_Diff_cycle(t *testing.T) {
+	a, b := &diffUser{Name: "a"}, &diffUser{Name: "a"}
+	a.Child, b.Child = a, b
+
+	d := NewDumper(new(bytes.Buffer), 2)
+	assert.Empty(t, d.Diffs(a, b))
+
+	b.Name = "b"
+	assert.Len(t, d.Diffs(a, b), 1)
+}
+
+func TestDumper_Fdiff(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := NewDumper(buf, 2)
+	d.NoColor = true
+	d.ShowFlag = Fnopos
+
+	d.Diff([]int{1, 2}, []int{1, 3, 4})
+	is.Equal("~ [1]: int(2) => int(3)\n+ [2]: int(4)\n", buf.String())
+
+	buf.Reset()
+	d.ShowFlag = Ffname | Fline
+	d.Fdiff(buf, "a", "a")
+	is.Contains(buf.String(), "PRINT AT diff_test.go:")
+	is.Contains(buf.String(), "no differences\n")
+}
@@ -64,6 +64,35 @@ func Fprint(w io.Writer, vs ...interface{}) {
 	std.Fprint(w, vs...)
 }
 
+// Sprint like fmt.Sprint, but the output is clearer and more beautiful.
+// the output has no caller position and color.
+func Sprint(vs ...interface{}) string {
+	return plainDumper(3).Sprint(vs...)
+}
+
+// Format the value to string by std dumper options, can custom the options by fns.
+// the output has no caller position and color, unless enable it by the fns.
+//
+// Usage:
+// 	str := dump.Format(user, func(opts *dump.Options) {
+// 		opts.Format = dump.FormatCompact
+// 	})
+func Format(v interface{}, fns ...func(opts *Options)) string {
+	d := plainDumper(3)
+	for _, fn := range fns {
+		fn(&d.Options)
+	}
+	return d.Sprint(v)
+}
+
+// new dumper by std options, but disable caller position and color.
+func plainDumper(skip int) *Dumper {
+	d := NewDumper(nil, skip).WithOptions(std.Options)
+	d.NoColor = true
+	d.ShowFlag = Fnopos
+	return d
+}
+
 func mustFprint(w io.Writer, v ...interface{}) {
 	_, _ = fmt.Fprint(w, v...)
 	// color.Fprint(w, v...)
@@ -1,6 +1,7 @@
 package dump
 
 import (
+	"bytes"
 	"io"
 	"path"
 	"reflect"
@@ -25,6 +26,34 @@ type Options struct {
 	ShowFlag int
 	// MoreLenNL array/slice elements length > MoreLenNL, will wrap new line
 	MoreLenNL int
+	// ShowAddr show the pointer address. eg: "*main.Node @0xc000010000 {"
+	ShowAddr bool
+	// Format the output format. allow: FormatGo, FormatJSON, FormatCompact
+	// or custom format by AddRenderer(). default is the human readable layout.
+	Format string
+	// IgnoreUnexported hide the unexported struct fields
+	IgnoreUnexported bool
+	// SkipFields skip the struct fields by name.
+	// the fields with tag `dump:"-"` are always skipped.
+	SkipFields []string
+	// MaskFields mask the struct fields or map keys value by name, case insensitive.
+	// the fields with tag `dump:"mask"` are always masked. see SensitiveFields
+	MaskFields []string
+	// MaskText for replace the masked value. default is DefaultMaskText
+	MaskText string
+	// Formatters custom formatters by value type, will override the global formatters.
+	// see AddFormatter()
+	Formatters map[reflect.Type]Formatter
+	// BytesFormat format for []byte value. allow: BytesAsHex, BytesAsString
+	BytesFormat string
+	// UseStringer use the String() of fmt.Stringer and Error() of error for format value
+	UseStringer bool
+	// MaxElements max elements to print for slice, array and map. 0 is unlimited.
+	MaxElements int
+	// MaxStringLen max bytes to print for string. 0 is unlimited.
+	MaxStringLen int
+	// MaxBytes max bytes to print for []byte. 0 is unlimited.
+	MaxBytes int
 	// CallerSkip skip for call runtime.Caller()
 	// CallerSkip int
 }
@@ -38,6 +67,9 @@ type Dumper struct {
 	// context information
 	prevDepth, curDepth, nextDepth int
 	indentStr, indentPrev, lineEnd string
+	// visited pointers of the current var, for mark cycle and shared references
+	refs   map[ptrKey]*ptrRef
+	nextID int
 }
 
 // NewDumper create
@@ -111,6 +143,18 @@ func (d *Dumper) Fprint(w io.Writer, vars ...interface{}) {
 	d.Output = out
 }
 
+// Sprint dump vars to string
+func (d *Dumper) Sprint(vars ...interface{}) string {
+	buf := new(bytes.Buffer)
+	out := d.Output
+
+	d.Output = buf
+	d.dump(vars...)
+
+	d.Output = out
+	return buf.String()
+}
+
 // dump vars
 func (d *Dumper) dump(vars ...interface{}) {
 	// show print position
@@ -124,10 +168,18 @@ func (d *Dumper) dump(vars ...interface{}) {
 
 	// print var data
 	for _, v := range vars {
+		if d.Format != FormatDefault {
+			d.render(v)
+			continue
+		}
+
+		d.refs, d.nextID = collectRefs(v, d.MaxDepth), 0
+
 		d.advance(1)
 		d.printOne(v)
 		d.advance(-1)
 	}
+	d.refs = nil
 }
 
 func (d *Dumper) advance(step int) {
@@ -200,6 +252,10 @@ func (d *Dumper) printOne(v interface{}) {
 		return
 	}
 
+	if d.printFormatted(reflect.ValueOf(v)) {
+		return
+	}
+
 	switch val := v.(type) {
 	case int, int8, int16, int32, int64:
 		mustFprintf(d.Output, "%s(%v),\n", reflect.TypeOf(val).String(), val)
@@ -208,10 +264,10 @@ func (d *Dumper) printOne(v interface{}) {
 	case float32, float64:
 		mustFprintf(d.Output, "%s(%v),\n", reflect.TypeOf(val).String(), val)
 	case string:
-		mustFprintf(d.Output, "string(\"%s\"),\n", val)
+		mustFprintf(d.Output, "string(%s),\n", d.quoteStr(val))
 	default: // must in switch, use asserted 'val' for continue handle
 		rTyp := reflect.TypeOf(val)
-		rVal := reflect.ValueOf(val)
+		rVal := addressable(reflect.ValueOf(val))
 		d.printReflectValue(rTyp, rVal)
 	}
 
@@ -222,8 +278,26 @@ func (d *Dumper) printOne(v interface{}) {
 
 func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 	var isPtr bool
+	var ptrMark string
 	// if is an ptr, get real type and value
 	if rTyp.Kind() == reflect.Ptr {
+		if rVal.IsNil() {
+			mustFprintf(d.Output, "%s(nil),\n", rTyp.String())
+			return
+		}
+
+		ref := d.visitPtr(rVal)
+		if ref != nil && ref.state != ptrUnvisited {
+			d.printPtrRef(rTyp, rVal, ref)
+			return
+		}
+
+		ptrMark = d.ptrMark(rVal, ref)
+		if ref != nil {
+			ref.state = ptrPrinting
+			defer func() { ref.state = ptrPrinted }()
+		}
+
 		isPtr = true
 		rVal = rVal.Elem()
 		rTyp = rTyp.Elem()
@@ -235,17 +309,29 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 
 	switch rTyp.Kind() {
 	case reflect.Slice, reflect.Array:
-		eleNum := rVal.Len()
-		if eleNum < d.MoreLenNL {
-			mustFprintf(w, "%#v,\n", rVal.Interface())
+		eleNum, limit := rVal.Len(), d.limitLen(rVal)
+		if isBytesType(rTyp) && limit < eleNum {
+			mustFprintf(w, "%#v%s,\n", rVal.Slice(0, limit), moreBytesMark(eleNum-limit, eleNum))
 			return
 		}
 
-		mustFprint(w, rTyp.String(), " [\n")
-		for i := 0; i < eleNum; i++ {
-			mustFprintf(w, "%s%v,\n", d.indentStr, rVal.Index(i).Interface())
+		if eleNum < d.MoreLenNL && limit == eleNum {
+			mustFprintf(w, "%#v,\n", rVal)
+			return
+		}
+
+		mustFprint(w, rTyp.String(), ptrMark, " [\n")
+		for i := 0; i < limit; i++ {
+			mustFprintf(w, "%s%v,\n", d.indentStr, rVal.Index(i))
+		}
+		if limit < eleNum {
+			mustFprintf(w, "%s%s\n", d.indentStr, moreElemsMark(eleNum-limit, eleNum))
+		}
+		if d.curDepth > 1 {
+			mustFprint(w, d.indentPrev, "],\n")
+		} else {
+			mustFprint(w, "]\n")
 		}
-		mustFprint(w, "]\n")
 	case reflect.Struct:
 		// refer https://stackoverflow.com/questions/42664837/how-to-access-unexported-struct-fields-in-golang
 		// NOTICE: only re-reflect.New on curDepth=1
@@ -259,15 +345,26 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 			// fmt.Println("aaa", ele.CanInterface())
 		}
 
-		mustFprint(w, rTyp.String(), " {\n")
+		mustFprint(w, rTyp.String(), ptrMark, " {\n")
 
 		fldNum := rVal.NumField()
 		for i := 0; i < fldNum; i++ {
 			fv := rVal.Field(i)
-			fieldName := rTyp.Field(i).Name
+			sf := rTyp.Field(i)
+			if d.skipField(sf) {
+				continue
+			}
+
 			// print field name
 			// mustFprintf(w, "%s<cyan>%s</>: ", indentStr, fieldName)
-			mustFprintf(w, "%s%s: ", d.indentStr, fieldName)
+			mustFprintf(w, "%s%s: ", d.indentStr, sf.Name)
+			if d.maskField(sf) {
+				mustFprint(w, d.maskText(), ",\n")
+				continue
+			}
+			if d.printFormatted(fv) {
+				continue
+			}
 
 			fTypeName := fv.Type().String()
 
@@ -276,7 +373,7 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 			case reflect.Bool:
 				mustFprintf(w, "%v,\n", fv.Bool())
 			case reflect.String:
-				mustFprintf(w, "%s(\"%s\"),\n", fTypeName, fv.String())
+				mustFprintf(w, "%s(%s),\n", fTypeName, d.quoteStr(fv.String()))
 			case reflect.Float32, reflect.Float64:
 				mustFprintf(w, "%s(%v),\n", fTypeName, fv.Float())
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -295,6 +392,24 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 					d.printReflectValue(fv.Type(), fv)
 					d.advance(-1)
 				}
+			case reflect.Slice, reflect.Array:
+				if d.exceedLimit(fv) {
+					d.advance(1)
+					d.printReflectValue(fv.Type(), fv)
+					d.advance(-1)
+				} else if fv.Kind() == reflect.Slice && fv.IsNil() {
+					mustFprint(w, "<nil>,\n")
+				} else {
+					mustFprintf(w, "%#v,\n", fv)
+				}
+			case reflect.Ptr:
+				if fv.IsNil() || d.curDepth > d.MaxDepth {
+					d.printPlain(fv)
+				} else {
+					d.advance(1)
+					d.printReflectValue(fv.Type(), fv)
+					d.advance(-1)
+				}
 			case reflect.Interface:
 				// field is `interface{}` type and cannot exported field in struct.
 				if !fv.CanInterface() {
@@ -323,9 +438,11 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 
 		mustFprint(w, d.indentPrev, "},\n")
 	case reflect.Map:
-		mustFprint(w, rTyp.String(), " {\n")
+		mustFprint(w, rTyp.String(), ptrMark, " {\n")
+
+		keys, limit := rVal.MapKeys(), d.limitLen(rVal)
 
-		for _, key := range rVal.MapKeys() {
+		for _, key := range keys[:limit] {
 			// print key name
 			if !key.CanInterface() {
 				// mustFprintf(w, "%s<cyan>%s</>: ", indentStr, key.String())
@@ -335,7 +452,15 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 				mustFprintf(w, "%s%#v: ", d.indentStr, key.Interface())
 			}
 
+			if d.maskKey(key) {
+				mustFprint(w, d.maskText(), ",\n")
+				continue
+			}
+
 			mv := rVal.MapIndex(key)
+			if d.printFormatted(mv) {
+				continue
+			}
 			vTypeName := mv.Type().String()
 
 			// print field value
@@ -343,7 +468,7 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 			case reflect.Bool:
 				mustFprintf(w, "%v,\n", mv.Bool())
 			case reflect.String:
-				mustFprintf(w, "%s(\"%s\"),\n", vTypeName, mv.String())
+				mustFprintf(w, "%s(%s),\n", vTypeName, d.quoteStr(mv.String()))
 			case reflect.Float32, reflect.Float64:
 				mustFprintf(w, "%s(%v),\n", vTypeName, mv.Float())
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -362,6 +487,24 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 					d.printReflectValue(mv.Type(), mv)
 					d.advance(-1)
 				}
+			case reflect.Slice, reflect.Array:
+				if d.exceedLimit(mv) {
+					d.advance(1)
+					d.printReflectValue(mv.Type(), mv)
+					d.advance(-1)
+				} else if mv.Kind() == reflect.Slice && mv.IsNil() {
+					mustFprint(w, "<nil>,\n")
+				} else {
+					mustFprintf(w, "%s(%#v),\n", vTypeName, mv)
+				}
+			case reflect.Ptr:
+				if mv.IsNil() || d.curDepth > d.MaxDepth {
+					d.printPlain(mv)
+				} else {
+					d.advance(1)
+					d.printReflectValue(mv.Type(), mv)
+					d.advance(-1)
+				}
 			case reflect.Interface:
 				if !mv.CanInterface() {
 					// refer https://stackoverflow.com/questions/42664837/how-to-access-unexported-struct-fields-in-golang
@@ -388,6 +531,9 @@ func (d *Dumper) printReflectValue(rTyp reflect.Type, rVal reflect.Value) {
 			}
 		}
 
+		if limit < len(keys) {
+			mustFprintf(w, "%s%s\n", d.indentStr, moreElemsMark(len(keys)-limit, len(keys)))
+		}
 		mustFprint(w, d.indentPrev, "},\n")
 	default:
 		// intX, uintX, string
@@ -0,0 +1,212 @@
+package dump
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// formats for the []byte value, set by Options.BytesFormat
+const (
+	BytesAsRaw    = ""
+	BytesAsHex    = "hex"
+	BytesAsString = "string"
+)
+
+// DefaultMaskText the text for replace the masked value
+const DefaultMaskText = "******"
+
+// SensitiveFields the common sensitive field names, can be used for Options.MaskFields
+var SensitiveFields = []string{"password", "passwd", "secret", "token", "apiKey", "accessKey", "privateKey"}
+
+// Formatter custom format the value to string
+type Formatter func(v interface{}) string
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// global formatters. the builtin formatters for time.Time, big.Int, big.Float, big.Rat.
+var formatters = map[reflect.Type]Formatter{
+	reflect.TypeOf(time.Time{}): func(v interface{}) string {
+		return v.(time.Time).Format(time.RFC3339Nano)
+	},
+	reflect.TypeOf(big.Int{}): func(v interface{}) string {
+		bi := v.(big.Int)
+		return bi.String()
+	},
+	reflect.TypeOf(big.Float{}): func(v interface{}) string {
+		bf := v.(big.Float)
+		return bf.String()
+	},
+	reflect.TypeOf(big.Rat{}): func(v interface{}) string {
+		br := v.(big.Rat)
+		return br.String()
+	},
+}
+
+// AddFormatter add global formatter for the type of the sample value.
+//
+// NOTICE: it is not concurrent safe, please call it on init.
+//
+// Usage:
+// 	dump.AddFormatter(decimal.Decimal{}, func(v interface{}) string {
+// 		return v.(decimal.Decimal).String()
+// 	})
+func AddFormatter(sample interface{}, fn Formatter) {
+	formatters[reflect.TypeOf(sample)] = fn
+}
+
+// check the struct field should be skipped
+func (o *Options) skipField(sf reflect.StructField) bool {
+	if sf.Tag.Get("dump") == "-" {
+		return true
+	}
+
+	if o.IgnoreUnexported && sf.PkgPath != "" {
+		return true
+	}
+
+	for _, name := range o.SkipFields {
+		if name == sf.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// check the struct field should be masked
+func (o *Options) maskField(sf reflect.StructField) bool {
+	return sf.Tag.Get("dump") == "mask" || o.maskName(sf.Name)
+}
+
+// check the field name or map key should be masked. case insensitive
+func (o *Options) maskName(name string) bool {
+	for _, mask := range o.MaskFields {
+		if strings.EqualFold(mask, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Options) maskText() string {
+	if o.MaskText != "" {
+		return o.MaskText
+	}
+	return DefaultMaskText
+}
+
+// check the map key should be masked, only for string key.
+func (o *Options) maskKey(key reflect.Value) bool {
+	if key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+	return key.Kind() == reflect.String && o.maskName(key.String())
+}
+
+// get the interface value of rv, the unexported field value will be read by unsafe.
+func valueInterface(rv reflect.Value) (interface{}, bool) {
+	if rv.CanInterface() {
+		return rv.Interface(), true
+	}
+
+	if rv.CanAddr() {
+		return reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem().Interface(), true
+	}
+	return nil, false
+}
+
+// copy struct value to an addressable value, for read the unexported fields by formatters
+func addressable(rv reflect.Value) reflect.Value {
+	if rv.Kind() != reflect.Struct || rv.CanAddr() {
+		return rv
+	}
+
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	return cp
+}
+
+// format value by the formatters, BytesFormat, Stringer and error.
+// returns false on the value is not formatted.
+func (o *Options) formatValue(rv reflect.Value) (string, bool) {
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return "", false
+	}
+
+	rt := rv.Type()
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", false
+		}
+
+		// only format the pointer of the formatter types. eg: *big.Int
+		if o.formatter(rt.Elem()) != nil {
+			rv, rt = rv.Elem(), rt.Elem()
+		}
+	}
+
+	if fn := o.formatter(rt); fn != nil {
+		if v, ok := valueInterface(rv); ok {
+			return fn(v), true
+		}
+	}
+
+	if rt == bytesType && o.BytesFormat != BytesAsRaw {
+		bs, more := o.truncBytes(rv.Bytes())
+		str := string(bs)
+		if o.BytesFormat == BytesAsHex {
+			str = hex.EncodeToString(bs)
+		}
+
+		if more > 0 {
+			str += moreBytesMark(more, rv.Len())
+		}
+		return str, true
+	}
+
+	if !o.UseStringer || (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return "", false
+	}
+
+	v, ok := valueInterface(rv)
+	if !ok {
+		return "", false
+	}
+
+	switch tv := v.(type) {
+	case error:
+		return tv.Error(), true
+	case fmt.Stringer:
+		return tv.String(), true
+	}
+	return "", false
+}
+
+func (o *Options) formatter(rt reflect.Type) Formatter {
+	if fn, ok := o.Formatters[rt]; ok {
+		return fn
+	}
+	return formatters[rt]
+}
+
+// print the formatted value. eg: "time.Time(2022-01-02T15:04:05Z)". returns false on not formatted.
+func (d *Dumper) printFormatted(rv reflect.Value) bool {
+	str, ok := d.formatValue(rv)
+	if !ok {
+		return false
+	}
+
+	if rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	mustFprintf(d.Output, "%s(%s),\n", rv.Type().String(), str)
+	return true
+}
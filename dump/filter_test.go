@@ -0,0 +1,159 @@
+package dump
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type filterStatus int
+
+func (s filterStatus) String() string {
+	return [...]string{"pending", "active"}[s]
+}
+
+type filterUser struct {
+	Name     string
+	Password string
+	Token    string `dump:"mask"`
+	Internal string `dump:"-"`
+	Status   filterStatus
+	Err      error
+	Created  time.Time
+	Balance  *big.Int
+	Raw      []byte
+	Extra    map[string]interface{}
+	secret   string
+	updated  time.Time
+}
+
+func newFilterUser() filterUser {
+	tm := time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)
+	return filterUser{
+		Name:     "inhere",
+		Password: "123456",
+		Token:    "abc-token",
+		Internal: "internal",
+		Status:   1,
+		Err:      errors.New("an error"),
+		Created:  tm,
+		Balance:  big.NewInt(1000),
+		Raw:      []byte("hi"),
+		Extra:    map[string]interface{}{"password": "654321", "city": "chengdu"},
+		secret:   "secret",
+		updated:  tm,
+	}
+}
+
+func newFilterDumper(buf *bytes.Buffer) *Dumper {
+	return NewDumper(buf, 2).Config(func(d *Dumper) {
+		d.NoColor = true
+		d.ShowFlag = Fnopos
+	})
+}
+
+func TestDumper_filterFields(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newFilterDumper(buf)
+	d.Dump(newFilterUser())
+
+	str := buf.String()
+	is.Contains(str, `Name: string("inhere"),`)
+	is.Contains(str, `Password: string("123456"),`)
+	is.Contains(str, "Token: ******,")
+	is.NotContains(str, "Internal")
+	is.Contains(str, "Created: time.Time(2022-01-02T15:04:05Z),")
+	is.Contains(str, "updated: time.Time(2022-01-02T15:04:05Z),")
+	is.Contains(str, "Balance: *big.Int(1000),")
+	is.Contains(str, "secret: string(\"secret\"),")
+
+	buf.Reset()
+	d.IgnoreUnexported = true
+	d.SkipFields = []string{"Err"}
+	d.MaskFields = SensitiveFields
+	d.MaskText = "***"
+	d.Dump(newFilterUser())
+
+	str = buf.String()
+	is.Contains(str, "Password: ***,")
+	is.Contains(str, "Token: ***,")
+	is.Contains(str, `"password": ***,`)
+	is.Contains(str, `"city": string("chengdu"),`)
+	is.NotContains(str, "Err:")
+	is.NotContains(str, "secret")
+	is.NotContains(str, "updated")
+}
+
+func TestDumper_formatters(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newFilterDumper(buf)
+	d.UseStringer = true
+	d.BytesFormat = BytesAsHex
+	d.Dump(newFilterUser())
+
+	str := buf.String()
+	is.Contains(str, "Status: dump.filterStatus(active),")
+	is.Contains(str, "Err: *errors.errorString(an error),")
+	is.Contains(str, "Raw: []uint8(6869),")
+
+	buf.Reset()
+	d.BytesFormat = BytesAsString
+	d.Formatters = map[reflect.Type]Formatter{
+		reflect.TypeOf(time.Time{}): func(v interface{}) string {
+			return v.(time.Time).Format("2006-01-02")
+		},
+	}
+	d.Dump(newFilterUser(), time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC))
+
+	str = buf.String()
+	is.Contains(str, "Raw: []uint8(hi),")
+	is.Contains(str, "Created: time.Time(2022-01-02),")
+	is.True(strings.HasSuffix(str, "time.Time(2022-03-04),\n"))
+
+	// global formatter
+	AddFormatter(filterStatus(0), func(v interface{}) string {
+		return "status-" + v.(filterStatus).String()
+	})
+	defer delete(formatters, reflect.TypeOf(filterStatus(0)))
+
+	buf.Reset()
+	d.Dump(newFilterUser())
+	is.Contains(buf.String(), "Status: dump.filterStatus(status-active),")
+}
+
+func TestDumper_filterRenderers(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newFilterDumper(buf)
+	d.MaskFields = []string{"password"}
+	d.IgnoreUnexported = true
+	d.SkipFields = []string{"Err", "Extra", "Raw"}
+
+	d.Format = FormatCompact
+	d.Dump(newFilterUser())
+	is.Equal(`dump.filterUser{Name:"inhere", Password:******, Token:******, Status:1, Created:2022-01-02T15:04:05Z, Balance:1000}`+"\n", buf.String())
+
+	buf.Reset()
+	d.Format = FormatJSON
+	d.Dump(newFilterUser())
+	is.Contains(buf.String(), `"Password": "******",`)
+	is.Contains(buf.String(), `"Created": "2022-01-02T15:04:05Z",`)
+	is.NotContains(buf.String(), "Internal")
+
+	buf.Reset()
+	d.Format = FormatGo
+	d.Dump(newFilterUser())
+	is.Contains(buf.String(), `Password: "******",`)
+	is.NotContains(buf.String(), "Internal")
+}
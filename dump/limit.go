@@ -0,0 +1,87 @@
+package dump
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// check is the []byte or [N]byte type
+func isBytesType(rt reflect.Type) bool {
+	kind := rt.Kind()
+	return (kind == reflect.Slice || kind == reflect.Array) && rt.Elem().Kind() == reflect.Uint8
+}
+
+// get the length to print for slice, array and map. limited by MaxElements, or MaxBytes for bytes.
+func (o *Options) limitLen(rv reflect.Value) int {
+	max := o.MaxElements
+	if o.MaxBytes > 0 && isBytesType(rv.Type()) {
+		max = o.MaxBytes
+	}
+
+	if n := rv.Len(); max <= 0 || n <= max {
+		return n
+	}
+	return max
+}
+
+// check the elements length exceeds the limit
+func (o *Options) exceedLimit(rv reflect.Value) bool {
+	return o.limitLen(rv) < rv.Len()
+}
+
+// truncate string by MaxStringLen, returns the truncated string and the more bytes count.
+// the string will be cut on the rune start.
+func (o *Options) truncString(str string) (string, int) {
+	if o.MaxStringLen <= 0 || len(str) <= o.MaxStringLen {
+		return str, 0
+	}
+
+	end := o.MaxStringLen
+	for end > 0 && !utf8.RuneStart(str[end]) {
+		end--
+	}
+	return str[:end], len(str) - end
+}
+
+// truncate bytes by MaxBytes, returns the truncated bytes and the more bytes count.
+func (o *Options) truncBytes(bs []byte) ([]byte, int) {
+	if o.MaxBytes <= 0 || len(bs) <= o.MaxBytes {
+		return bs, 0
+	}
+	return bs[:o.MaxBytes], len(bs) - o.MaxBytes
+}
+
+// marker for the truncated string. eg: "... 95 more bytes, len=100"
+func moreBytesMark(more, total int) string {
+	return fmt.Sprintf("... %d more bytes, len=%d", more, total)
+}
+
+// marker for the truncated elements. eg: "... 95 more, len=100"
+func moreElemsMark(more, total int) string {
+	return fmt.Sprintf("... %d more, len=%d", more, total)
+}
+
+// quote string for the human layout, will truncate by MaxStringLen.
+// eg: `"abc"`, `"abc"... 95 more bytes, len=100`
+func (o *Options) quoteStr(str string) string {
+	sub, more := o.truncString(str)
+	if more == 0 {
+		return `"` + str + `"`
+	}
+	return `"` + sub + `"` + moreBytesMark(more, len(str))
+}
+
+// quote string for the renderers by the quote func, will truncate by MaxStringLen.
+// if inner is true, the marker will be inside the quoted string.
+func (o *Options) quoteWith(str string, quote func(string) string, inner bool) string {
+	sub, more := o.truncString(str)
+	if more == 0 {
+		return quote(str)
+	}
+
+	if inner {
+		return quote(sub + moreBytesMark(more, len(str)))
+	}
+	return quote(sub) + moreBytesMark(more, len(str))
+}
@@ -0,0 +1,116 @@
+package dump
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type limitData struct {
+	Name string
+	Ids  []int
+	Raw  []byte
+	Opts map[string]int
+}
+
+func newLimitData() limitData {
+	return limitData{
+		Name: strings.Repeat("ab", 10),
+		Ids:  []int{1, 2, 3, 4, 5, 6},
+		Raw:  []byte("hello world"),
+		Opts: map[string]int{"a": 1, "b": 2, "c": 3, "d": 4},
+	}
+}
+
+func newLimitDumper(buf *bytes.Buffer) *Dumper {
+	return NewDumper(buf, 2).Config(func(d *Dumper) {
+		d.NoColor = true
+		d.ShowFlag = Fnopos
+		d.MaxElements = 3
+		d.MaxStringLen = 5
+		d.MaxBytes = 4
+	})
+}
+
+func TestOptions_truncString(t *testing.T) {
+	is := assert.New(t)
+	opts := Options{MaxStringLen: 4}
+
+	str, more := opts.truncString("abc")
+	is.Equal("abc", str)
+	is.Equal(0, more)
+
+	str, more = opts.truncString("abcdef")
+	is.Equal("abcd", str)
+	is.Equal(2, more)
+
+	// not cut in the middle of rune
+	str, more = opts.truncString("中文")
+	is.Equal("中", str)
+	is.Equal(3, more)
+
+	opts.MaxStringLen = 0
+	str, more = opts.truncString("abcdef")
+	is.Equal("abcdef", str)
+	is.Equal(0, more)
+}
+
+func TestDumper_limits(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newLimitDumper(buf)
+	d.Dump(newLimitData(), []int{1, 2, 3, 4, 5}, "中文中文")
+
+	str := buf.String()
+	is.Contains(str, `Name: string("ababa"... 15 more bytes, len=20),`)
+	is.Contains(str, "    3,\n    ... 3 more, len=6\n  ],\n")
+	is.Contains(str, "Raw: []uint8{0x68, 0x65, 0x6c, 0x6c}... 7 more bytes, len=11,")
+	is.Contains(str, "    ... 1 more, len=4\n  },\n")
+	is.Equal(3, strings.Count(str, ": int("))
+	is.Contains(str, "  3,\n  ... 2 more, len=5\n]\n")
+	is.Contains(str, `string("中"... 9 more bytes, len=12),`)
+
+	// hex bytes format
+	buf.Reset()
+	d.BytesFormat = BytesAsHex
+	d.Dump([]byte("hello world"))
+	is.Equal("[]uint8(68656c6c... 7 more bytes, len=11),\n", buf.String())
+
+	// no limits
+	buf.Reset()
+	d = newLimitDumper(buf)
+	d.MaxElements, d.MaxStringLen, d.MaxBytes = 0, 0, 0
+	d.Dump(newLimitData())
+	is.NotContains(buf.String(), "more")
+	is.Contains(buf.String(), "Ids: []int{1, 2, 3, 4, 5, 6},")
+}
+
+func TestDumper_limitRenderers(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newLimitDumper(buf)
+	d.Format = FormatCompact
+	d.Dump(newLimitData())
+	is.Equal(`dump.limitData{Name:"ababa"... 15 more bytes, len=20, Ids:[1, 2, 3, ... 3 more], Raw:[104, 101, 108, 108, ... 7 more], Opts:{"a":1, "b":2, "c":3, ... 1 more}}`+"\n", buf.String())
+
+	buf.Reset()
+	d.Format = FormatJSON
+	d.Dump(newLimitData())
+	str := buf.String()
+	is.Contains(str, `"Name": "ababa... 15 more bytes, len=20",`)
+	is.Contains(str, "    3,\n    \"... 3 more, len=6\"\n  ],")
+	is.Contains(str, "    \"c\": 3,\n    \"@more\": 1\n  }")
+
+	buf.Reset()
+	d.Format = FormatGo
+	d.Dump(newLimitData())
+	str = buf.String()
+	is.Contains(str, `Name: "ababa... 15 more bytes, len=20",`)
+	is.Contains(str, "Ids: []int{1, 2, 3 /* ... 3 more, len=6 */},")
+	is.Contains(str, "Raw: []uint8{104, 101, 108, 108 /* ... 7 more, len=11 */},")
+	is.Contains(str, "    \"c\": 3,\n    /* ... 1 more, len=4 */\n  },")
+}
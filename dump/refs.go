@@ -0,0 +1,122 @@
+package dump
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// states of the visited pointer
+const (
+	ptrUnvisited = iota
+	ptrPrinting
+	ptrPrinted
+)
+
+// pointer identity. NOTICE: the struct and it's first field has same address, so must with type.
+type ptrKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+type ptrRef struct {
+	// id for the back reference marker, 0 is not assigned.
+	id int
+	// count of the pointer be referenced
+	count int
+	state int
+}
+
+// collect the pointers referenced by the value, for find out the cycle and shared references.
+func collectRefs(v interface{}, maxDepth int) map[ptrKey]*ptrRef {
+	refs := make(map[ptrKey]*ptrRef)
+	if v != nil {
+		walkRefs(reflect.ValueOf(v), refs, 1, maxDepth)
+	}
+	return refs
+}
+
+func walkRefs(rv reflect.Value, refs map[ptrKey]*ptrRef, depth, maxDepth int) {
+	if depth > maxDepth+1 {
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return
+		}
+
+		key := ptrKey{rv.Pointer(), rv.Type()}
+		if ref, ok := refs[key]; ok {
+			ref.count++
+			return
+		}
+
+		refs[key] = &ptrRef{count: 1}
+		walkRefs(rv.Elem(), refs, depth, maxDepth)
+	case reflect.Interface:
+		if !rv.IsNil() {
+			walkRefs(rv.Elem(), refs, depth, maxDepth)
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			walkRefs(rv.Field(i), refs, depth+1, maxDepth)
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			walkRefs(iter.Value(), refs, depth+1, maxDepth)
+		}
+	}
+}
+
+// get the ref info of the pointer, returns nil on the pointer is referenced once.
+func (d *Dumper) visitPtr(rv reflect.Value) *ptrRef {
+	ref := d.refs[ptrKey{rv.Pointer(), rv.Type()}]
+	if ref == nil || ref.count < 2 {
+		return nil
+	}
+
+	if ref.id == 0 {
+		d.nextID++
+		ref.id = d.nextID
+	}
+	return ref
+}
+
+// mark for the pointer value header. eg: " #1 @0xc000010000"
+func (d *Dumper) ptrMark(rv reflect.Value, ref *ptrRef) (mark string) {
+	if ref != nil {
+		mark = fmt.Sprintf(" #%d", ref.id)
+	}
+
+	if d.ShowAddr {
+		mark += fmt.Sprintf(" @%#x", rv.Pointer())
+	}
+	return
+}
+
+// print back reference marker. eg: "*main.Node <cycle #1>,"
+func (d *Dumper) printPtrRef(rTyp reflect.Type, rv reflect.Value, ref *ptrRef) {
+	kind := "ref"
+	if ref.state == ptrPrinting {
+		kind = "cycle"
+	}
+
+	mark := fmt.Sprintf("<%s #%d>", kind, ref.id)
+	if d.ShowAddr {
+		mark += fmt.Sprintf(" @%#x", rv.Pointer())
+	}
+	mustFprintf(d.Output, "%s %s,\n", rTyp.String(), mark)
+}
+
+// print value without expand
+func (d *Dumper) printPlain(rv reflect.Value) {
+	if rv.IsNil() {
+		mustFprint(d.Output, "<nil>,\n")
+	} else if rv.CanInterface() {
+		mustFprintf(d.Output, "%#v,\n", rv.Interface())
+	} else {
+		mustFprintf(d.Output, "%#v,\n", rv.String())
+	}
+}
@@ -0,0 +1,81 @@
+package dump
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type refNode struct {
+	Name string
+	Next *refNode
+	Data map[string]interface{}
+}
+
+func newRefDumper(buf *bytes.Buffer) *Dumper {
+	return NewDumper(buf, 2).Config(func(d *Dumper) {
+		d.NoColor = true
+		d.ShowFlag = Fnopos
+	})
+}
+
+func TestDumper_cycleRef(t *testing.T) {
+	is := assert.New(t)
+
+	a := &refNode{Name: "a"}
+	b := &refNode{Name: "b", Next: a}
+	a.Next = b
+
+	buf := new(bytes.Buffer)
+	newRefDumper(buf).Dump(a)
+
+	str := buf.String()
+	is.Equal(`*dump.refNode #1 {
+  Name: string("a"),
+  Next: *dump.refNode {
+    Name: string("b"),
+    Next: *dump.refNode <cycle #1>,
+    Data: map[string]interface {} {
+    },
+  },
+  Data: map[string]interface {} {
+  },
+},
+`, str)
+}
+
+func TestDumper_sharedRef(t *testing.T) {
+	is := assert.New(t)
+
+	shared := &refNode{Name: "shared"}
+	root := &refNode{Name: "root", Data: map[string]interface{}{"first": shared}, Next: shared}
+
+	buf := new(bytes.Buffer)
+	d := newRefDumper(buf)
+	d.Dump(root)
+
+	str := buf.String()
+	is.Equal(1, bytes.Count(buf.Bytes(), []byte(`Name: string("shared")`)))
+	is.Contains(str, "*dump.refNode #1 {")
+	is.Contains(str, "*dump.refNode <ref #1>,")
+	is.NotContains(str, "<cycle")
+
+	// show address
+	buf.Reset()
+	d.ShowAddr = true
+	d.Dump(root)
+	str = buf.String()
+	is.Contains(str, fmt.Sprintf("*dump.refNode #1 @%p {", shared))
+	is.Contains(str, fmt.Sprintf("*dump.refNode <ref #1> @%p,", shared))
+	is.Contains(str, fmt.Sprintf("*dump.refNode @%p {", root))
+}
+
+func TestDumper_nilPtr(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var node *refNode
+
+	newRefDumper(buf).Dump(node)
+	assert.Equal(t, "*dump.refNode(nil),\n", buf.String())
+}
@@ -0,0 +1,322 @@
+package dump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// builtin output formats, set by Options.Format
+const (
+	// FormatDefault the human readable layout of the Dumper
+	FormatDefault = ""
+	// FormatGo valid Go literal syntax, can paste back into the code
+	FormatGo = "go"
+	// FormatJSON JSON with type annotations
+	FormatJSON = "json"
+	// FormatCompact single line, for log lines
+	FormatCompact = "compact"
+)
+
+// Renderer render the value to writer
+type Renderer interface {
+	Render(w io.Writer, v interface{}, opts Options) error
+}
+
+// RendererFunc wrap func as Renderer
+type RendererFunc func(w io.Writer, v interface{}, opts Options) error
+
+// Render the value
+func (fn RendererFunc) Render(w io.Writer, v interface{}, opts Options) error {
+	return fn(w, v, opts)
+}
+
+var renderers = map[string]Renderer{
+	FormatGo:      RendererFunc(renderGo),
+	FormatJSON:    RendererFunc(renderJSON),
+	FormatCompact: RendererFunc(renderCompact),
+}
+
+// AddRenderer add custom renderer for the format name.
+//
+// NOTICE: it is not concurrent safe, please call it on init.
+func AddRenderer(format string, r Renderer) {
+	renderers[format] = r
+}
+
+// GetRenderer get renderer by format name, returns nil on not found.
+func GetRenderer(format string) Renderer {
+	return renderers[format]
+}
+
+// render value by the Options.Format renderer
+func (d *Dumper) render(v interface{}) {
+	r := renderers[d.Format]
+	if r == nil {
+		mustFprintf(d.Output, "dump: unknown format %q\n", d.Format)
+		return
+	}
+
+	if err := r.Render(d.Output, v, d.Options); err != nil {
+		mustFprintf(d.Output, "dump: render error: %s\n", err.Error())
+	}
+}
+
+// renderState the common state for the builtin renderers
+type renderState struct {
+	Options
+	buf *bytes.Buffer
+	// the pointers on the current path, for detect cycle references
+	stack map[ptrKey]bool
+}
+
+func newRenderState(opts Options) *renderState {
+	return &renderState{
+		Options: opts,
+		buf:     new(bytes.Buffer),
+		stack:   make(map[ptrKey]bool),
+	}
+}
+
+func (s *renderState) flush(w io.Writer) error {
+	s.buf.WriteByte('\n')
+	_, err := s.buf.WriteTo(w)
+	return err
+}
+
+func (s *renderState) write(ss ...string) {
+	for _, str := range ss {
+		s.buf.WriteString(str)
+	}
+}
+
+func (s *renderState) indent(depth int) string {
+	char := s.IndentChar
+	if char == 0 {
+		char = ' '
+	}
+	return strings.Repeat(string(char), s.IndentLen*depth)
+}
+
+// enter pointer, returns false on the pointer is on the current path.
+func (s *renderState) enter(rv reflect.Value) bool {
+	key := ptrKey{rv.Pointer(), rv.Type()}
+	if s.stack[key] {
+		return false
+	}
+
+	s.stack[key] = true
+	return true
+}
+
+func (s *renderState) leave(rv reflect.Value) {
+	delete(s.stack, ptrKey{rv.Pointer(), rv.Type()})
+}
+
+// get map keys sorted by the kind and value
+func sortedMapKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+	return keys
+}
+
+// compare the map keys. the keys are ordered by the kind first, then by the value,
+// other kinds are compared by the string value.
+func lessKey(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface && !a.IsNil() {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface && !b.IsNil() {
+		b = b.Elem()
+	}
+
+	if a.Kind() != b.Kind() {
+		return a.Kind() < b.Kind()
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// format scalar value, returns false on the value is not scalar.
+// the string is quoted, the float always has decimal point.
+func formatScalar(rv reflect.Value) (string, bool) {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		str := strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits())
+		if !strings.ContainsAny(str, ".eIN") {
+			str += ".0"
+		}
+		return str, true
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(rv.Complex(), 'g', -1, rv.Type().Bits()), true
+	case reflect.String:
+		return strconv.Quote(rv.String()), true
+	}
+	return "", false
+}
+
+//
+// ------------------ compact renderer ------------------
+//
+
+// eg: `&main.User{Name:"inhere", Age:22, Tags:["a", "b"]}`
+func renderCompact(w io.Writer, v interface{}, opts Options) error {
+	s := newRenderState(opts)
+	s.compact(addressable(reflect.ValueOf(v)), 0)
+	return s.flush(w)
+}
+
+func (s *renderState) compact(rv reflect.Value, depth int) {
+	if !rv.IsValid() {
+		s.write("nil")
+		return
+	}
+
+	if str, ok := s.formatValue(rv); ok {
+		s.write(str)
+		return
+	}
+
+	if rv.Kind() == reflect.String {
+		s.write(s.quoteWith(rv.String(), strconv.Quote, false))
+		return
+	}
+
+	if str, ok := formatScalar(rv); ok {
+		s.write(str)
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			s.write("nil")
+			return
+		}
+
+		if rv.Kind() == reflect.Interface {
+			s.compact(rv.Elem(), depth)
+			return
+		}
+
+		if !s.enter(rv) {
+			s.write("<cycle>")
+			return
+		}
+		defer s.leave(rv)
+
+		s.write("&")
+		s.compact(rv.Elem(), depth)
+	case reflect.Struct:
+		if depth >= s.MaxDepth {
+			s.write(rv.Type().String(), "{...}")
+			return
+		}
+
+		s.write(rv.Type().String(), "{")
+		rt, n := rv.Type(), 0
+		for i := 0; i < rv.NumField(); i++ {
+			sf := rt.Field(i)
+			if s.skipField(sf) {
+				continue
+			}
+
+			if n++; n > 1 {
+				s.write(", ")
+			}
+			s.write(sf.Name, ":")
+
+			if s.maskField(sf) {
+				s.write(s.maskText())
+			} else {
+				s.compact(rv.Field(i), depth+1)
+			}
+		}
+		s.write("}")
+	case reflect.Map:
+		if rv.IsNil() {
+			s.write("nil")
+			return
+		}
+		if depth >= s.MaxDepth {
+			s.write("{...}")
+			return
+		}
+
+		s.write("{")
+		keys, limit := sortedMapKeys(rv), s.limitLen(rv)
+		for i, key := range keys[:limit] {
+			if i > 0 {
+				s.write(", ")
+			}
+			s.compact(key, depth+1)
+			s.write(":")
+
+			if s.maskKey(key) {
+				s.write(s.maskText())
+			} else {
+				s.compact(rv.MapIndex(key), depth+1)
+			}
+		}
+		s.compactMore(limit, len(keys))
+		s.write("}")
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			s.write("nil")
+			return
+		}
+		if depth >= s.MaxDepth {
+			s.write("[...]")
+			return
+		}
+
+		s.write("[")
+		limit := s.limitLen(rv)
+		for i := 0; i < limit; i++ {
+			if i > 0 {
+				s.write(", ")
+			}
+			s.compact(rv.Index(i), depth+1)
+		}
+		s.compactMore(limit, rv.Len())
+		s.write("]")
+	default: // chan, func, unsafe pointer
+		s.write(fmt.Sprintf("%s(%#x)", rv.Type().String(), rv.Pointer()))
+	}
+}
+
+// write the more marker for the truncated elements. eg: ", ... 97 more"
+func (s *renderState) compactMore(limit, total int) {
+	if limit == total {
+		return
+	}
+
+	if limit > 0 {
+		s.write(", ")
+	}
+	s.write(fmt.Sprintf("... %d more", total-limit))
+}
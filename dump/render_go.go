@@ -0,0 +1,270 @@
+package dump
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// the types of untyped constant default type, no need convert on Go syntax.
+var untypedDefaults = map[reflect.Type]bool{
+	reflect.TypeOf(false): true,
+	reflect.TypeOf(0):     true,
+	reflect.TypeOf(0.0):   true,
+	reflect.TypeOf(""):    true,
+}
+
+// render as valid Go literal syntax. the cycle references of the pointer, map
+// and slice will be rendered as nil, the NaN and Inf float as math.NaN(), math.Inf(1).
+//
+// eg:
+// 	&main.User{
+// 		Name: "inhere",
+// 		Tags: []string{"a", "b"},
+// 	}
+func renderGo(w io.Writer, v interface{}, opts Options) error {
+	s := newRenderState(opts)
+	s.goSyntax(addressable(reflect.ValueOf(v)), 0, false)
+	return s.flush(w)
+}
+
+// typeKnown - the type can be inferred from the context. eg: elements of the []int
+func (s *renderState) goSyntax(rv reflect.Value, depth int, typeKnown bool) {
+	if !rv.IsValid() {
+		s.write("nil")
+		return
+	}
+
+	rt := rv.Type()
+	if lit, ok := goSpecialFloat(rv); ok {
+		// math.NaN() is float64, other float types must be converted
+		if rt == reflect.TypeOf(0.0) {
+			s.write(lit)
+		} else {
+			s.write(rt.String(), "(", lit, ")")
+		}
+		return
+	}
+
+	if lit, ok := formatScalar(rv); ok {
+		if rv.Kind() == reflect.String {
+			lit = s.quoteWith(rv.String(), strconv.Quote, true)
+		}
+
+		if typeKnown || untypedDefaults[rt] {
+			s.write(lit)
+		} else {
+			s.write(rt.String(), "(", lit, ")")
+		}
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			s.write("nil")
+		} else {
+			s.goSyntax(rv.Elem(), depth, false)
+		}
+	case reflect.Ptr:
+		if rv.IsNil() {
+			if typeKnown {
+				s.write("nil")
+			} else {
+				s.write("(", rt.String(), ")(nil)")
+			}
+			return
+		}
+
+		if !s.enter(rv) {
+			s.write("nil /* cycle */")
+			return
+		}
+		defer s.leave(rv)
+
+		switch rt.Elem().Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+			s.write("&")
+			s.goSyntax(rv.Elem(), depth, false)
+		default: // pointer to scalar value
+			s.write("func() ", rt.String(), " { v := ")
+			s.goSyntax(rv.Elem(), depth, false)
+			s.write("; return &v }()")
+		}
+	case reflect.Struct:
+		if !typeKnown {
+			s.write(rt.String())
+		}
+
+		if rv.NumField() == 0 {
+			s.write("{}")
+			return
+		}
+
+		s.write("{\n")
+		for i := 0; i < rv.NumField(); i++ {
+			sf := rt.Field(i)
+			if s.skipField(sf) {
+				continue
+			}
+
+			// the masked field will keep zero value, except string field
+			fv := rv.Field(i)
+			if s.maskField(sf) {
+				if fv.Kind() == reflect.String {
+					s.write(s.indent(depth+1), sf.Name, ": ", strconv.Quote(s.maskText()), ",\n")
+				}
+				continue
+			}
+
+			s.write(s.indent(depth+1), sf.Name, ": ")
+			s.goSyntax(fv, depth+1, false)
+			s.write(",\n")
+		}
+		s.write(s.indent(depth), "}")
+	case reflect.Map:
+		if rv.IsNil() {
+			s.goNil(rt, typeKnown)
+			return
+		}
+
+		if !s.enter(rv) {
+			s.goNil(rt, typeKnown)
+			s.write(" /* cycle */")
+			return
+		}
+		defer s.leave(rv)
+
+		if !typeKnown {
+			s.write(rt.String())
+		}
+		if rv.Len() == 0 {
+			s.write("{}")
+			return
+		}
+
+		s.write("{\n")
+		keys, limit := sortedMapKeys(rv), s.limitLen(rv)
+		for _, key := range keys[:limit] {
+			mv := rv.MapIndex(key)
+			if s.maskKey(key) {
+				// only the string value can be masked
+				if mv.Kind() == reflect.Interface && !mv.IsNil() {
+					mv = mv.Elem()
+				}
+				if mv.Kind() != reflect.String {
+					continue
+				}
+				mv = reflect.ValueOf(s.maskText()).Convert(mv.Type())
+			}
+
+			s.write(s.indent(depth + 1))
+			s.goSyntax(key, depth+1, true)
+			s.write(": ")
+			s.goSyntax(mv, depth+1, true)
+			s.write(",\n")
+		}
+		if limit < len(keys) {
+			s.write(s.indent(depth+1), s.goMore(limit, len(keys)), "\n")
+		}
+		s.write(s.indent(depth), "}")
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			s.goNil(rt, typeKnown)
+			return
+		}
+
+		// the array is a value, only the slice can reference itself
+		if rv.Kind() == reflect.Slice && rv.Len() > 0 {
+			if !s.enter(rv) {
+				s.goNil(rt, typeKnown)
+				s.write(" /* cycle */")
+				return
+			}
+			defer s.leave(rv)
+		}
+
+		if !typeKnown {
+			s.write(rt.String())
+		}
+
+		// inline the scalar elements. eg: []int{1, 2, 3}
+		if isScalarKind(rt.Elem().Kind()) {
+			s.write("{")
+			limit := s.limitLen(rv)
+			for i := 0; i < limit; i++ {
+				if i > 0 {
+					s.write(", ")
+				}
+				s.goSyntax(rv.Index(i), depth+1, true)
+			}
+			if limit < rv.Len() {
+				s.write(" ", s.goMore(limit, rv.Len()))
+			}
+			s.write("}")
+			return
+		}
+
+		if rv.Len() == 0 {
+			s.write("{}")
+			return
+		}
+
+		s.write("{\n")
+		limit := s.limitLen(rv)
+		for i := 0; i < limit; i++ {
+			s.write(s.indent(depth + 1))
+			s.goSyntax(rv.Index(i), depth+1, true)
+			s.write(",\n")
+		}
+		if limit < rv.Len() {
+			s.write(s.indent(depth+1), s.goMore(limit, rv.Len()), "\n")
+		}
+		s.write(s.indent(depth), "}")
+	default: // chan, func, unsafe pointer can not be literal
+		s.write("nil")
+	}
+}
+
+// the more marker comment for the truncated elements. eg: "/* ... 97 more, len=100 */"
+func (s *renderState) goMore(limit, total int) string {
+	return "/* " + moreElemsMark(total-limit, total) + " */"
+}
+
+func (s *renderState) goNil(rt reflect.Type, typeKnown bool) {
+	if typeKnown {
+		s.write("nil")
+	} else {
+		s.write(rt.String(), "(nil)")
+	}
+}
+
+// the NaN and Inf float can not be literal, use the math func call instead.
+func goSpecialFloat(rv reflect.Value) (string, bool) {
+	if rv.Kind() != reflect.Float32 && rv.Kind() != reflect.Float64 {
+		return "", false
+	}
+
+	f := rv.Float()
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()", true
+	case math.IsInf(f, 1):
+		return "math.Inf(1)", true
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)", true
+	}
+	return "", false
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
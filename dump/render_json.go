@@ -0,0 +1,170 @@
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// TypeKey the type annotation key for the JSON format
+var TypeKey = "@type"
+
+// render as JSON, the objects will have type annotation field TypeKey.
+//
+// eg:
+// 	{
+// 	  "@type": "*main.User",
+// 	  "Name": "inhere",
+// 	  "Tags": ["a", "b"]
+// 	}
+func renderJSON(w io.Writer, v interface{}, opts Options) error {
+	s := newRenderState(opts)
+	s.json(addressable(reflect.ValueOf(v)), 0, "")
+	return s.flush(w)
+}
+
+func jsonString(str string) string {
+	bs, _ := json.Marshal(str)
+	return string(bs)
+}
+
+// ptrType the pointer type name for the annotation. eg: "*main.User"
+func (s *renderState) json(rv reflect.Value, depth int, ptrType string) {
+	if !rv.IsValid() {
+		s.write("null")
+		return
+	}
+
+	if str, ok := s.formatValue(rv); ok {
+		s.write(jsonString(str))
+		return
+	}
+
+	rt := rv.Type()
+	typName := rt.String()
+	if ptrType != "" {
+		typName = ptrType
+	}
+
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		// JSON not support the NaN and Inf
+		if f := rv.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			s.write(jsonString(fmt.Sprint(f)))
+			return
+		}
+	case reflect.Complex64, reflect.Complex128:
+		s.write(jsonString(fmt.Sprint(rv.Complex())))
+		return
+	case reflect.Interface:
+		if rv.IsNil() {
+			s.write("null")
+		} else {
+			s.json(rv.Elem(), depth, "")
+		}
+		return
+	case reflect.Ptr:
+		if rv.IsNil() {
+			s.write("null")
+			return
+		}
+
+		if !s.enter(rv) {
+			s.write(`{`, jsonString(TypeKey), `: `, jsonString(typName), `, "@cycle": true}`)
+			return
+		}
+		defer s.leave(rv)
+
+		s.json(rv.Elem(), depth, typName)
+		return
+	case reflect.Struct:
+		if depth >= s.MaxDepth {
+			s.write(`{`, jsonString(TypeKey), `: `, jsonString(typName), `, "@more": true}`)
+			return
+		}
+
+		s.write("{\n", s.indent(depth+1), jsonString(TypeKey), ": ", jsonString(typName))
+		for i := 0; i < rv.NumField(); i++ {
+			sf := rt.Field(i)
+			if s.skipField(sf) {
+				continue
+			}
+
+			s.write(",\n", s.indent(depth+1), jsonString(sf.Name), ": ")
+			if s.maskField(sf) {
+				s.write(jsonString(s.maskText()))
+			} else {
+				s.json(rv.Field(i), depth+1, "")
+			}
+		}
+		s.write("\n", s.indent(depth), "}")
+		return
+	case reflect.Map:
+		if rv.IsNil() {
+			s.write("null")
+			return
+		}
+		if depth >= s.MaxDepth {
+			s.write(`{`, jsonString(TypeKey), `: `, jsonString(typName), `, "@more": true}`)
+			return
+		}
+
+		s.write("{\n", s.indent(depth+1), jsonString(TypeKey), ": ", jsonString(typName))
+		keys, limit := sortedMapKeys(rv), s.limitLen(rv)
+		for _, key := range keys[:limit] {
+			s.write(",\n", s.indent(depth+1), jsonString(fmt.Sprint(key)), ": ")
+			if s.maskKey(key) {
+				s.write(jsonString(s.maskText()))
+			} else {
+				s.json(rv.MapIndex(key), depth+1, "")
+			}
+		}
+		if limit < len(keys) {
+			s.write(",\n", s.indent(depth+1), `"@more": `, fmt.Sprint(len(keys)-limit))
+		}
+		s.write("\n", s.indent(depth), "}")
+		return
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			s.write("null")
+			return
+		}
+		if rv.Len() == 0 {
+			s.write("[]")
+			return
+		}
+		if depth >= s.MaxDepth {
+			s.write(`["..."]`)
+			return
+		}
+
+		s.write("[\n")
+		limit := s.limitLen(rv)
+		for i := 0; i < limit; i++ {
+			if i > 0 {
+				s.write(",\n")
+			}
+			s.write(s.indent(depth + 1))
+			s.json(rv.Index(i), depth+1, "")
+		}
+		if limit < rv.Len() {
+			if limit > 0 {
+				s.write(",\n")
+			}
+			s.write(s.indent(depth+1), jsonString(moreElemsMark(rv.Len()-limit, rv.Len())))
+		}
+		s.write("\n", s.indent(depth), "]")
+		return
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		s.write(jsonString(fmt.Sprintf("%s(%#x)", typName, rv.Pointer())))
+		return
+	}
+
+	lit, _ := formatScalar(rv)
+	if rv.Kind() == reflect.String {
+		lit = s.quoteWith(rv.String(), jsonString, true)
+	}
+	s.write(lit)
+}
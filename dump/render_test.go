@@ -0,0 +1,176 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type renderUser struct {
+	Name  string
+	Age   int
+	Score float64
+	Tags  []string
+	Meta  map[string]interface{}
+	Next  *renderUser
+	level uint8
+}
+
+func newRenderUser() *renderUser {
+	return &renderUser{
+		Name:  "inhere",
+		Age:   22,
+		Score: 90,
+		Tags:  []string{"go", "php"},
+		Meta:  map[string]interface{}{"city": "chengdu", "rate": int8(3)},
+		level: 2,
+	}
+}
+
+func newFormatDumper(buf *bytes.Buffer, format string) *Dumper {
+	return NewDumper(buf, 2).Config(func(d *Dumper) {
+		d.ShowFlag = Fnopos
+		d.Format = format
+	})
+}
+
+func TestDumper_FormatGo(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newFormatDumper(buf, FormatGo)
+	d.Dump(newRenderUser())
+
+	str := buf.String()
+	is.Equal(`&dump.renderUser{
+  Name: "inhere",
+  Age: 22,
+  Score: 90.0,
+  Tags: []string{"go", "php"},
+  Meta: map[string]interface {}{
+    "city": "chengdu",
+    "rate": int8(3),
+  },
+  Next: (*dump.renderUser)(nil),
+  level: uint8(2),
+}
+`, str)
+
+	// must be valid Go syntax
+	_, err := parser.ParseExpr(str)
+	is.NoError(err)
+
+	num := 23
+	for _, v := range []interface{}{[]*int{&num}, []renderUser{{Name: "a"}}, map[int]bool(nil)} {
+		buf.Reset()
+		d.Dump(v)
+
+		_, err = parser.ParseExpr(buf.String())
+		is.NoError(err)
+		str += buf.String()
+	}
+
+	is.Contains(str, "[]*int{\n  func() *int { v := 23; return &v }(),\n}\n")
+	is.Contains(str, "[]dump.renderUser{\n  {\n    Name: \"a\",")
+	is.Contains(str, "map[int]bool(nil)\n")
+
+	// cycle
+	buf.Reset()
+	u := newRenderUser()
+	u.Next = u
+	d.Dump(u)
+	is.Contains(buf.String(), "Next: nil /* cycle */,")
+
+	// map and slice cycle
+	m := map[string]interface{}{"a": 1}
+	m["self"] = m
+	sl := []interface{}{1, nil}
+	sl[1] = sl
+	buf.Reset()
+	d.Dump(m, sl)
+	is.Contains(buf.String(), `"self": map[string]interface {}(nil) /* cycle */,`)
+	is.Contains(buf.String(), "  []interface {}(nil) /* cycle */,")
+
+	// NaN and Inf float
+	buf.Reset()
+	d.Dump([]interface{}{math.NaN(), math.Inf(-1), float32(math.Inf(1))})
+	is.Contains(buf.String(), "  math.NaN(),\n  math.Inf(-1),\n  float32(math.Inf(1)),")
+	_, err = parser.ParseExpr(buf.String())
+	is.NoError(err)
+}
+
+func TestDumper_FormatJSON(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	u := newRenderUser()
+	u.Next = u
+	u.Score = math.Inf(1)
+
+	d := newFormatDumper(buf, FormatJSON)
+	d.Dump(u)
+
+	var data map[string]interface{}
+	is.NoError(json.Unmarshal(buf.Bytes(), &data))
+	is.Equal("*dump.renderUser", data["@type"])
+	is.Equal("inhere", data["Name"])
+	is.Equal(float64(22), data["Age"])
+	is.Equal("+Inf", data["Score"])
+	is.Equal([]interface{}{"go", "php"}, data["Tags"])
+	is.Equal(map[string]interface{}{"@type": "map[string]interface {}", "city": "chengdu", "rate": float64(3)}, data["Meta"])
+	is.Equal(map[string]interface{}{"@type": "*dump.renderUser", "@cycle": true}, data["Next"])
+
+	buf.Reset()
+	d.MaxDepth = 1
+	d.Dump(map[string]interface{}{"a": map[string]int{"b": 1}, "c": nil})
+	is.Contains(buf.String(), `"a": {"@type": "map[string]int", "@more": true}`)
+	is.Contains(buf.String(), `"c": null`)
+}
+
+func TestDumper_FormatCompact(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	d := newFormatDumper(buf, FormatCompact)
+	d.Dump(newRenderUser(), []int{1, 2}, nil)
+	is.Equal(`&dump.renderUser{Name:"inhere", Age:22, Score:90.0, Tags:["go", "php"], Meta:{"city":"chengdu", "rate":3}, Next:nil, level:2}
+[1, 2]
+nil
+`, buf.String())
+
+	// use Fprint write to other writer
+	buf.Reset()
+	out := new(bytes.Buffer)
+	d.Fprint(out, map[string]int{"b": 2, "a": 1})
+	is.Equal("{\"a\":1, \"b\":2}\n", out.String())
+	is.Empty(buf.String())
+}
+
+func TestAddRenderer(t *testing.T) {
+	is := assert.New(t)
+
+	AddRenderer("upper", RendererFunc(func(w io.Writer, v interface{}, opts Options) error {
+		_, err := io.WriteString(w, strings.ToUpper(v.(string))+"\n")
+		return err
+	}))
+	defer delete(renderers, "upper")
+
+	is.NotNil(GetRenderer("upper"))
+	is.Nil(GetRenderer("not-exists"))
+
+	buf := new(bytes.Buffer)
+	d := newFormatDumper(buf, "upper")
+	d.Dump("abc")
+	is.Equal("ABC\n", buf.String())
+
+	buf.Reset()
+	d.Format = "not-exists"
+	d.Dump("abc")
+	is.Equal("dump: unknown format \"not-exists\"\n", buf.String())
+}
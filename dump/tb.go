@@ -0,0 +1,56 @@
+package dump
+
+import (
+	"strings"
+)
+
+// TB the minimal interface of the testing.TB, use for log the dump output.
+type TB interface {
+	Helper()
+	Log(args ...interface{})
+}
+
+// T dump vars to the test log by tb.Log(). the log line is the caller line of T.
+//
+// Usage:
+// 	func TestSome(t *testing.T) {
+// 		dump.T(t, user)
+// 	}
+func T(tb TB, vs ...interface{}) {
+	tb.Helper()
+	tb.Log("\n" + Sprint(vs...))
+}
+
+// TDiff log the differences of two values to the test log by tb.Log()
+func TDiff(tb TB, expected, actual interface{}) {
+	tb.Helper()
+	plainDumper(3).TDiff(tb, expected, actual)
+}
+
+// T dump vars to the test log by tb.Log(), by the dumper options.
+// the caller position is not printed, it is shown by the test log.
+func (d *Dumper) T(tb TB, vars ...interface{}) {
+	tb.Helper()
+	tb.Log("\n" + d.withoutPos().Sprint(vars...))
+}
+
+// TDiff log the differences between expected and actual to the test log by tb.Log()
+func (d *Dumper) TDiff(tb TB, expected, actual interface{}) {
+	tb.Helper()
+
+	items := d.Diffs(expected, actual)
+	if len(items) == 0 {
+		return
+	}
+
+	buf := new(strings.Builder)
+	d.printDiffs(buf, items)
+	tb.Log("\n" + buf.String())
+}
+
+// copy the dumper and disable print the caller position
+func (d *Dumper) withoutPos() *Dumper {
+	cp := *d
+	cp.ShowFlag = Fnopos
+	return &cp
+}
@@ -0,0 +1,77 @@
+package dump
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTB record the logs and the Helper() calls
+type fakeTB struct {
+	logs    []string
+	helpers int
+}
+
+func (f *fakeTB) Helper() {
+	f.helpers++
+}
+
+func (f *fakeTB) Log(args ...interface{}) {
+	f.logs = append(f.logs, args[0].(string))
+}
+
+func TestSprint(t *testing.T) {
+	is := assert.New(t)
+
+	str := Sprint(23, "abc")
+	is.Equal("int(23),\nstring(\"abc\"),\n", str)
+
+	str = Format([]int{1, 2}, func(opts *Options) {
+		opts.Format = FormatCompact
+	})
+	is.Equal("[1, 2]\n", str)
+
+	str = Format("abc", func(opts *Options) {
+		opts.ShowFlag = Ffname | Fline
+	})
+	is.True(strings.HasPrefix(str, "PRINT AT tb_test.go:"))
+	is.NotContains(str, "\x1b[")
+
+	// dumper Sprint
+	d := NewDumper(nil, 2).Config(func(d *Dumper) {
+		d.ShowFlag = Ffname
+	})
+	str = d.Sprint(23)
+	is.Contains(str, "PRINT AT tb_test.go")
+	is.Nil(d.Output)
+}
+
+func TestT(t *testing.T) {
+	is := assert.New(t)
+	tb := &fakeTB{}
+
+	T(tb, 23)
+	is.Equal([]string{"\nint(23),\n"}, tb.logs)
+	is.Equal(1, tb.helpers)
+
+	tb.logs = nil
+	TDiff(tb, []int{1, 2}, []int{1, 3})
+	is.Equal([]string{"\n~ [1]: int(2) => int(3)\n"}, tb.logs)
+
+	tb.logs = nil
+	TDiff(tb, []int{1, 2}, []int{1, 2})
+	is.Empty(tb.logs)
+
+	// the dumper options
+	tb.logs = nil
+	d := NewDumper(nil, 2).Config(func(d *Dumper) {
+		d.NoColor = true
+		d.Format = FormatCompact
+	})
+	d.T(tb, []int{1, 2})
+	is.Equal([]string{"\n[1, 2]\n"}, tb.logs)
+
+	// real test log
+	T(t, map[string]int{"a": 1})
+}
@@ -1,9 +1,10 @@
-package envutil
+package envutil_test
 
 import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/envutil"
 	"github.com/urionz/goutil/testutil"
 )
 
@@ -29,34 +30,34 @@ func TestParseEnvValue(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		ris.Equal("", Getenv(tt.eKey))
+		ris.Equal("", envutil.Getenv(tt.eKey))
 
 		testutil.MockEnvValue(tt.eKey, tt.eVal, func(eVal string) {
 			ris.Equal(tt.eVal, eVal)
-			ris.Equal(tt.nVal, ParseEnvValue(tt.rVal))
+			ris.Equal(tt.nVal, envutil.ParseEnvValue(tt.rVal))
 		})
 	}
 
 	// test multi ENV key
 	rVal := "${FirstEnv}/${ SecondEnv }"
-	ris.Equal("", Getenv("FirstEnv"))
-	ris.Equal("", Getenv("SecondEnv"))
-	ris.Equal(rVal, ParseEnvValue(rVal))
+	ris.Equal("", envutil.Getenv("FirstEnv"))
+	ris.Equal("", envutil.Getenv("SecondEnv"))
+	ris.Equal(rVal, envutil.ParseEnvValue(rVal))
 
 	testutil.MockEnvValues(map[string]string{
 		"FirstEnv":  "abc",
 		"SecondEnv": "def",
 	}, func() {
-		ris.Equal("abc", Getenv("FirstEnv"))
-		ris.Equal("def", Getenv("SecondEnv"))
-		ris.Equal("abc/def", ParseEnvValue(rVal))
+		ris.Equal("abc", envutil.Getenv("FirstEnv"))
+		ris.Equal("def", envutil.Getenv("SecondEnv"))
+		ris.Equal("abc/def", envutil.ParseEnvValue(rVal))
 	})
 
 	testutil.MockEnvValues(map[string]string{
 		"FirstEnv": "abc",
 	}, func() {
-		ris.Equal("abc", Getenv("FirstEnv"))
-		ris.Equal("", Getenv("SecondEnv"))
-		ris.Equal("abc/${ SecondEnv }", ParseEnvValue(rVal))
+		ris.Equal("abc", envutil.Getenv("FirstEnv"))
+		ris.Equal("", envutil.Getenv("SecondEnv"))
+		ris.Equal("abc/${ SecondEnv }", envutil.ParseEnvValue(rVal))
 	})
 }
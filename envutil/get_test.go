@@ -1,9 +1,10 @@
-package envutil
+package envutil_test
 
 import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/envutil"
 	"github.com/urionz/goutil/testutil"
 )
 
@@ -18,9 +19,9 @@ func TestGetenv(t *testing.T) {
 	testutil.MockEnvValues(map[string]string{
 		TestEnvName: TestEnvValue,
 	}, func() {
-		envValue := Getenv(TestEnvName)
+		envValue := envutil.Getenv(TestEnvName)
 		assert.Equal(t, TestEnvValue, envValue, "env value not equals")
-		envValue = Getenv(TestNoEnvName, DefaultTestEnvValue)
+		envValue = envutil.Getenv(TestNoEnvName, DefaultTestEnvValue)
 		assert.Equal(t, DefaultTestEnvValue, envValue, "env value not default")
 	})
 }
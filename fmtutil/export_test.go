@@ -0,0 +1,16 @@
+package fmtutil
+
+// RemoveTimeLocale remove the locale, for clean up the test locales.
+func RemoveTimeLocale(name string) {
+	delete(timeLocales, name)
+}
+
+// RenderBar render the progress bar
+func (p *Progress) RenderBar() string {
+	return p.renderBar()
+}
+
+// Frame get the spinner frame by index
+func (s *Spinner) Frame(i int) string {
+	return s.frame(i)
+}
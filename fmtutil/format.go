@@ -1,32 +1,26 @@
 package fmtutil
 
 import (
-	"encoding/json"
-	"fmt"
 	"strconv"
+
+	"github.com/urionz/goutil/internal/jsonpretty"
 )
 
-// DataSize format bytes number friendly.
+// DataSize format bytes number friendly. use the DefaultSizeFormatter
 // Usage:
 // 	file, err := os.Open(path)
 // 	fl, err := file.Stat()
 // 	fmtSize := DataSize(fl.Size())
 func DataSize(bytes uint64) string {
-	switch {
-	case bytes < 1024:
-		return fmt.Sprintf("%dB", bytes)
-	case bytes < 1024*1024:
-		return fmt.Sprintf("%.2fK", float64(bytes)/1024)
-	case bytes < 1024*1024*1024:
-		return fmt.Sprintf("%.2fM", float64(bytes)/1024/1024)
-	default:
-		return fmt.Sprintf("%.2fG", float64(bytes)/1024/1024/1024)
-	}
+	return DefaultSizeFormatter.Format(bytes)
 }
 
 // PrettyJSON get pretty Json string
+// Deprecated
+//  please use jsonutil.Pretty() or jsonutil.PrettyWith() instead it
 func PrettyJSON(v interface{}) (string, error) {
-	out, err := json.MarshalIndent(v, "", "    ")
+	// same as jsonutil.Pretty(), the jsonutil can not be imported on here.
+	out, err := jsonpretty.Marshal(v, &jsonpretty.Options{Indent: "    ", Theme: &jsonpretty.Theme{}})
 	return string(out), err
 }
 
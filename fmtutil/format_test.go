@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/urionz/goutil/fmtutil"
+	"github.com/urionz/goutil/jsonutil"
 )
 
 func TestDataSize(t *testing.T) {
@@ -39,6 +40,13 @@ func TestPrettyJSON(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, want, got)
 	}
+
+	// same as the jsonutil.Pretty()
+	sample := map[string]interface{}{"a": []int{}, "b": map[string]string{"c": "<d>"}}
+	got, err := fmtutil.PrettyJSON(sample)
+	assert.NoError(t, err)
+	want, _ = jsonutil.Pretty(sample)
+	assert.Equal(t, want, got)
 }
 
 func TestStringsToInts(t *testing.T) {
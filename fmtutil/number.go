@@ -0,0 +1,279 @@
+package fmtutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// NumberSeparators mapping locale name to {decimal separator, thousands separator}
+var NumberSeparators = map[string][2]string{
+	"en": {".", ","},
+	"zh": {".", ","},
+	"ja": {".", ","},
+	"de": {",", "."},
+	"es": {",", "."},
+	"it": {",", "."},
+	"fr": {",", " "},
+	"ru": {",", " "},
+	"ch": {".", "'"},
+}
+
+// NumberFormatter for format number with thousands separators
+type NumberFormatter struct {
+	// Decimals the decimal places. default is 0
+	Decimals int
+	// DecimalSep decimal separator. default is "."
+	DecimalSep string
+	// ThousandsSep thousands separator. default is ","
+	ThousandsSep string
+}
+
+// NewNumberFormatter create
+func NewNumberFormatter(fns ...func(nf *NumberFormatter)) *NumberFormatter {
+	nf := &NumberFormatter{
+		DecimalSep:   ".",
+		ThousandsSep: ",",
+	}
+
+	for _, fn := range fns {
+		fn(nf)
+	}
+	return nf
+}
+
+// WithLocale set separators by locale name. see NumberSeparators
+func (nf *NumberFormatter) WithLocale(locale string) *NumberFormatter {
+	if seps, ok := NumberSeparators[locale]; ok {
+		nf.DecimalSep, nf.ThousandsSep = seps[0], seps[1]
+	}
+	return nf
+}
+
+// Format the number
+func (nf *NumberFormatter) Format(num float64) string {
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+
+	str := strconv.FormatFloat(math.Abs(num), 'f', nf.Decimals, 64)
+	intPart, fracPart := str, ""
+	if pos := strings.IndexByte(str, '.'); pos > 0 {
+		intPart, fracPart = str[:pos], str[pos+1:]
+	}
+
+	var sb strings.Builder
+	if num < 0 && strings.Trim(str, "0.") != "" {
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString(groupThousands(intPart, nf.ThousandsSep))
+	if fracPart != "" {
+		sb.WriteString(nf.DecimalSep)
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// FormatNumber format number with thousands separators.
+// the locale is optional, see NumberSeparators.
+//
+// Usage:
+// 	FormatNumber(1234567.891, 2) // "1,234,567.89"
+// 	FormatNumber(1234567.891, 2, "de") // "1.234.567,89"
+func FormatNumber(num float64, decimals int, locale ...string) string {
+	nf := NewNumberFormatter(func(nf *NumberFormatter) {
+		nf.Decimals = decimals
+	})
+
+	if len(locale) > 0 {
+		nf.WithLocale(locale[0])
+	}
+	return nf.Format(num)
+}
+
+// FormatInt format int number with thousands separators. eg: "1,234,567"
+func FormatInt(num int64) string {
+	str := strconv.FormatInt(num, 10)
+	if num < 0 {
+		return "-" + groupThousands(str[1:], ",")
+	}
+	return groupThousands(str, ",")
+}
+
+// insert the separator to the digits every 3 chars from right. eg: "1234" => "1,234"
+func groupThousands(digits, sep string) string {
+	var sb strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// the SI prefixes for compact number
+const siPrefixes = "kMGTPE"
+
+// CompactNumber format number to compact SI form. the tail zeros of decimal will be trimmed.
+//
+// Usage:
+// 	CompactNumber(1234, 1) // "1.2k"
+// 	CompactNumber(3400000, 1) // "3.4M"
+// 	CompactNumber(2000, 1) // "2k"
+func CompactNumber(num float64, precision int) string {
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+
+	var exp int
+	if abs := math.Abs(num); abs >= 1000 {
+		exp = int(math.Log10(abs) / 3)
+		if exp > len(siPrefixes) {
+			exp = len(siPrefixes)
+		}
+	}
+
+	for {
+		str := strconv.FormatFloat(num/math.Pow(1000, float64(exp)), 'f', precision, 64)
+
+		// rounding up to next unit. eg: 999.96 -> "1000" -> "1k", 999999 -> "1000k" -> "1M"
+		rounded, _ := strconv.ParseFloat(str, 64)
+		if math.Abs(rounded) >= 1000 && exp < len(siPrefixes) {
+			exp++
+			continue
+		}
+
+		if exp == 0 {
+			return trimZeros(str)
+		}
+		return trimZeros(str) + siPrefixes[exp-1:exp]
+	}
+}
+
+func trimZeros(str string) string {
+	if strings.IndexByte(str, '.') > 0 {
+		str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+	}
+	return str
+}
+
+// Ordinal get the english ordinal number string.
+//
+// Usage:
+// 	Ordinal(1) // "1st"
+// 	Ordinal(12) // "12th"
+// 	Ordinal(23) // "23rd"
+func Ordinal(num int) string {
+	abs := num % 100
+	if abs < 0 {
+		abs = -abs
+	}
+
+	suffix := "th"
+	if abs < 11 || abs > 13 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(num) + suffix
+}
+
+// FormatPercent format the percent value. eg: mathutil.Percent() result
+//
+// Usage:
+// 	FormatPercent(45.678, 1) // "45.7%"
+func FormatPercent(val float64, decimals int) string {
+	return strconv.FormatFloat(val, 'f', decimals, 64) + "%"
+}
+
+// FormatRatio format the ratio as percent. eg: 0.4567 -> "45.67%"
+func FormatRatio(ratio float64, decimals int) string {
+	return FormatPercent(ratio*100, decimals)
+}
+
+// NumberFuncs the number format functions for text template.
+// they are builtin functions of the strutil.RenderText()
+//
+// Usage:
+// 	tpl := template.New("demo").Funcs(fmtutil.NumberFuncs())
+// 	template.Must(tpl.Parse(`{{ number .Total 2 }}`))
+//
+// Functions:
+// 	number(num, decimals...) "1,234.50"
+// 	compactNum(num, precision...) "1.2k"
+// 	ordinal(num) "2nd"
+// 	percent(val, decimals...) "45.60%"
+// 	dataSize(bytes) "1.50K"
+func NumberFuncs() template.FuncMap {
+	return template.FuncMap{
+		"number": func(num interface{}, decimals ...int) string {
+			return FormatNumber(toFloat(num), optInt(decimals, 0))
+		},
+		"compactNum": func(num interface{}, precision ...int) string {
+			return CompactNumber(toFloat(num), optInt(precision, 1))
+		},
+		"ordinal": func(num interface{}) string {
+			return Ordinal(int(toFloat(num)))
+		},
+		"percent": func(val interface{}, decimals ...int) string {
+			return FormatPercent(toFloat(val), optInt(decimals, 2))
+		},
+		"dataSize": func(bytes interface{}) string {
+			return DataSize(uint64(toFloat(bytes)))
+		},
+	}
+}
+
+func optInt(opts []int, def int) int {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return def
+}
+
+// convert number value to float64 for template functions
+func toFloat(val interface{}) float64 {
+	switch tVal := val.(type) {
+	case int:
+		return float64(tVal)
+	case int8:
+		return float64(tVal)
+	case int16:
+		return float64(tVal)
+	case int32:
+		return float64(tVal)
+	case int64:
+		return float64(tVal)
+	case uint:
+		return float64(tVal)
+	case uint8:
+		return float64(tVal)
+	case uint16:
+		return float64(tVal)
+	case uint32:
+		return float64(tVal)
+	case uint64:
+		return float64(tVal)
+	case float32:
+		return float64(tVal)
+	case float64:
+		return tVal
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(tVal), 64)
+		if err == nil {
+			return f
+		}
+	}
+
+	f, _ := strconv.ParseFloat(fmt.Sprint(val), 64)
+	return f
+}
@@ -0,0 +1,96 @@
+package fmtutil_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fmtutil"
+)
+
+func TestFormatNumber(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("0", fmtutil.FormatNumber(0, 0))
+	is.Equal("123", fmtutil.FormatNumber(123, 0))
+	is.Equal("1,234", fmtutil.FormatNumber(1234, 0))
+	is.Equal("1,234,567.89", fmtutil.FormatNumber(1234567.891, 2))
+	is.Equal("-1,234,567.9", fmtutil.FormatNumber(-1234567.891, 1))
+	is.Equal("0.00", fmtutil.FormatNumber(-0.001, 2))
+	is.Equal("1.234.567,89", fmtutil.FormatNumber(1234567.891, 2, "de"))
+	is.Equal("1 234 567,89", fmtutil.FormatNumber(1234567.891, 2, "fr"))
+	is.Equal("NaN", fmtutil.FormatNumber(math.NaN(), 2))
+	is.Equal("123,456,789", fmtutil.FormatInt(123456789))
+	is.Equal("9,007,199,254,740,993", fmtutil.FormatInt(1<<53+1))
+	is.Equal("-9,223,372,036,854,775,808", fmtutil.FormatInt(math.MinInt64))
+	is.Equal("-12", fmtutil.FormatInt(-12))
+
+	nf := fmtutil.NewNumberFormatter(func(nf *fmtutil.NumberFormatter) {
+		nf.Decimals = 3
+		nf.ThousandsSep = "_"
+	})
+	is.Equal("12_345.679", nf.Format(12345.6789))
+}
+
+func TestCompactNumber(t *testing.T) {
+	tests := []struct {
+		give float64
+		want string
+	}{
+		{12, "12"},
+		{999.94, "999.9"},
+		{999.96, "1k"},
+		{-999.96, "-1k"},
+		{1000, "1k"},
+		{1234, "1.2k"},
+		{-1234, "-1.2k"},
+		{3400000, "3.4M"},
+		{999999, "1M"},
+		{2.5e9, "2.5G"},
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "+Inf"},
+		{math.Inf(-1), "-Inf"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fmtutil.CompactNumber(tt.give, 1))
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int]string{
+		0:   "0th",
+		1:   "1st",
+		2:   "2nd",
+		3:   "3rd",
+		4:   "4th",
+		11:  "11th",
+		12:  "12th",
+		13:  "13th",
+		21:  "21st",
+		102: "102nd",
+		111: "111th",
+		-1:  "-1st",
+	}
+
+	for give, want := range tests {
+		assert.Equal(t, want, fmtutil.Ordinal(give))
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	assert.Equal(t, "45.7%", fmtutil.FormatPercent(45.678, 1))
+	assert.Equal(t, "45.67%", fmtutil.FormatRatio(0.4567, 2))
+}
+
+func TestNumberFuncs(t *testing.T) {
+	tpl := template.Must(template.New("test").Funcs(fmtutil.NumberFuncs()).Parse(
+		`{{ number .N }}|{{ number .N 1 }}|{{ compactNum .N }}|{{ ordinal 3 }}|{{ percent 12.5 }}|{{ number "12345" }}`,
+	))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, tpl.Execute(buf, map[string]interface{}{"N": int64(12345)}))
+	assert.Equal(t, "12,345|12,345.0|12.3k|3rd|12.50%|12,345", buf.String())
+}
@@ -0,0 +1,501 @@
+package fmtutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urionz/goutil/envutil"
+)
+
+// clear the current terminal line and move cursor to the line start.
+const clearLine = "\r\x1b[K"
+
+// Progress bar for long-running tasks. it's safe for concurrent use.
+//
+// On the output is not a terminal(checked by envutil.IsConsole), will
+// degrade to print plain lines periodically.
+//
+// Usage:
+// 	p := fmtutil.NewProgress(fileSize, func(p *fmtutil.Progress) {
+// 		p.Title = "downloading"
+// 		p.Bytes = true
+// 	})
+// 	_, err := io.Copy(dst, p.ProxyReader(resp.Body))
+// 	p.Finish()
+type Progress struct {
+	// Title message before the bar
+	Title string
+	// Total the max steps or bytes. 0 is unknown, will not display the bar.
+	Total int64
+	// Width of the bar. default is 40
+	Width int
+	// Bytes format the current and total as data size, by DataSize()
+	Bytes bool
+	// BarChars chars of the bar: completed, current, remaining. default is "=", ">", " "
+	BarChars [3]string
+	// Out writer for render. default is os.Stderr
+	Out io.Writer
+	// Plain force use the plain line mode
+	Plain bool
+	// RefreshInterval min interval for redraw on terminal. default is 100ms
+	RefreshInterval time.Duration
+	// LineInterval min interval for print plain line. default is 5s
+	LineInterval time.Duration
+
+	mu       sync.Mutex
+	current  int64
+	started  time.Time
+	lastDraw time.Time
+	finished bool
+}
+
+// NewProgress create progress bar
+func NewProgress(total int64, fns ...func(p *Progress)) *Progress {
+	p := &Progress{
+		Total:           total,
+		Width:           defaultBarWidth,
+		BarChars:        [3]string{"=", ">", " "},
+		Out:             os.Stderr,
+		RefreshInterval: 100 * time.Millisecond,
+		LineInterval:    5 * time.Second,
+	}
+
+	for _, fn := range fns {
+		fn(p)
+	}
+	return p
+}
+
+// Start the progress, will auto start on the first Add().
+func (p *Progress) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.start()
+	p.draw(true)
+}
+
+func (p *Progress) start() {
+	if p.started.IsZero() {
+		p.started = time.Now()
+	}
+}
+
+// Advance add one step
+func (p *Progress) Advance() {
+	p.Add(1)
+}
+
+// Add steps to current
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.start()
+	p.current += n
+	p.draw(false)
+}
+
+// Set the current steps
+func (p *Progress) Set(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.start()
+	p.current = n
+	p.draw(false)
+}
+
+// Write implements the io.Writer, add len(bs) to current.
+//
+// Usage:
+// 	io.Copy(io.MultiWriter(dst, p), src)
+func (p *Progress) Write(bs []byte) (int, error) {
+	p.Add(int64(len(bs)))
+	return len(bs), nil
+}
+
+// ProxyReader wrap the reader, add read bytes to current.
+func (p *Progress) ProxyReader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+// ProxyWriter wrap the writer, add written bytes to current.
+func (p *Progress) ProxyWriter(w io.Writer) io.Writer {
+	return &progressWriter{w: w, p: p}
+}
+
+// Finish the progress, render the final status.
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+
+	p.start()
+	if p.Total > 0 && p.current < p.Total {
+		p.current = p.Total
+	}
+
+	p.draw(true)
+	p.finished = true
+	if p.isTTY() {
+		_, _ = io.WriteString(p.Out, "\n")
+	}
+}
+
+// Current steps
+func (p *Progress) Current() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// Percent of the current. returns 0 on total is unknown.
+func (p *Progress) Percent() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.percent()
+}
+
+func (p *Progress) percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.current) / float64(p.Total) * 100
+}
+
+// Elapsed time since started
+func (p *Progress) Elapsed() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.elapsed()
+}
+
+func (p *Progress) elapsed() time.Duration {
+	if p.started.IsZero() {
+		return 0
+	}
+	return time.Since(p.started)
+}
+
+// Rate steps per second
+func (p *Progress) Rate() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rate()
+}
+
+func (p *Progress) rate() float64 {
+	sec := p.elapsed().Seconds()
+	if sec <= 0 {
+		return 0
+	}
+	return float64(p.current) / sec
+}
+
+// ETA estimated remaining time. returns -1 on can not estimate.
+func (p *Progress) ETA() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.eta()
+}
+
+func (p *Progress) eta() time.Duration {
+	rate := p.rate()
+	if p.Total <= 0 || rate <= 0 {
+		return -1
+	}
+
+	remain := p.Total - p.current
+	if remain <= 0 {
+		return 0
+	}
+	return time.Duration(float64(remain) / rate * float64(time.Second))
+}
+
+// String render progress status line
+func (p *Progress) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.render(false)
+}
+
+func (p *Progress) isTTY() bool {
+	return !p.Plain && envutil.IsConsole(p.Out)
+}
+
+func (p *Progress) draw(force bool) {
+	if p.finished {
+		return
+	}
+
+	tty := p.isTTY()
+	interval := p.LineInterval
+	if tty {
+		interval = p.RefreshInterval
+	}
+
+	now := time.Now()
+	if !force && !p.lastDraw.IsZero() && now.Sub(p.lastDraw) < interval {
+		return
+	}
+	p.lastDraw = now
+
+	if tty {
+		_, _ = io.WriteString(p.Out, clearLine+p.render(true))
+	} else {
+		_, _ = io.WriteString(p.Out, p.render(false)+"\n")
+	}
+}
+
+func (p *Progress) formatNum(n int64) string {
+	if p.Bytes {
+		return DataSize(uint64(n))
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+func (p *Progress) render(withBar bool) string {
+	var ss []string
+	if p.Title != "" {
+		ss = append(ss, p.Title)
+	}
+
+	if p.Total > 0 {
+		if withBar {
+			ss = append(ss, "["+p.renderBar()+"]")
+		}
+		ss = append(ss, fmt.Sprintf("%6.2f%%", p.percent()))
+		ss = append(ss, p.formatNum(p.current)+"/"+p.formatNum(p.Total))
+	} else {
+		ss = append(ss, p.formatNum(p.current))
+	}
+
+	if rate := p.rate(); rate > 0 {
+		if p.Bytes {
+			ss = append(ss, DataSize(uint64(rate))+"/s")
+		} else {
+			ss = append(ss, fmt.Sprintf("%.1f/s", rate))
+		}
+	}
+
+	if eta := p.eta(); eta > 0 {
+		ss = append(ss, "ETA "+HowLongAgo(int64(eta.Seconds())))
+	} else if p.Total <= 0 && !p.started.IsZero() {
+		ss = append(ss, "elapsed "+HowLongAgo(int64(p.elapsed().Seconds())))
+	}
+
+	return strings.Join(ss, " ")
+}
+
+const defaultBarWidth = 40
+
+func (p *Progress) renderBar() string {
+	width := p.Width
+	if width <= 0 {
+		width = defaultBarWidth
+	}
+
+	done := int(float64(width) * p.percent() / 100)
+	if done < 0 {
+		done = 0
+	} else if done > width {
+		done = width
+	}
+
+	if done == width {
+		return strings.Repeat(p.BarChars[0], done)
+	}
+	return strings.Repeat(p.BarChars[0], done) + p.BarChars[1] + strings.Repeat(p.BarChars[2], width-done-1)
+}
+
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+func (pr *progressReader) Read(bs []byte) (int, error) {
+	n, err := pr.r.Read(bs)
+	pr.p.Add(int64(n))
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *Progress
+}
+
+func (pw *progressWriter) Write(bs []byte) (int, error) {
+	n, err := pw.w.Write(bs)
+	pw.p.Add(int64(n))
+	return n, err
+}
+
+// builtin spinner frames
+var (
+	SpinnerLine    = []string{"|", "/", "-", "\\"}
+	SpinnerDots    = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerArrows  = []string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}
+	SpinnerBouncer = []string{"[    ]", "[=   ]", "[==  ]", "[=== ]", "[ ===]", "[  ==]", "[   =]"}
+)
+
+// Spinner for the indeterminate work.
+//
+// On the output is not a terminal(checked by envutil.IsConsole), will
+// degrade to print plain lines periodically.
+//
+// Usage:
+// 	s := fmtutil.NewSpinner("waiting for server")
+// 	s.Start()
+// 	// do something ...
+// 	s.Stop("server is ready")
+type Spinner struct {
+	// Frames of the spinner. default is SpinnerLine
+	Frames []string
+	// Interval for redraw on terminal. default is 100ms
+	Interval time.Duration
+	// LineInterval interval for print plain line. default is 5s
+	LineInterval time.Duration
+	// Out writer for render. default is os.Stderr
+	Out io.Writer
+	// Plain force use the plain line mode
+	Plain bool
+
+	mu      sync.Mutex
+	title   string
+	started time.Time
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSpinner create
+func NewSpinner(title string, fns ...func(s *Spinner)) *Spinner {
+	s := &Spinner{
+		Frames:       SpinnerLine,
+		Interval:     100 * time.Millisecond,
+		LineInterval: 5 * time.Second,
+		Out:          os.Stderr,
+		title:        title,
+	}
+
+	for _, fn := range fns {
+		fn(s)
+	}
+	return s
+}
+
+// SetTitle update the spinner title message
+func (s *Spinner) SetTitle(title string) {
+	s.mu.Lock()
+	s.title = title
+	s.mu.Unlock()
+}
+
+// Title get the spinner title message
+func (s *Spinner) Title() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.title
+}
+
+// Running check spinner is running
+func (s *Spinner) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stop != nil
+}
+
+// Start the spinner in background goroutine
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.started = time.Now()
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.run(s.stop, s.done)
+}
+
+func (s *Spinner) run(stop, done chan struct{}) {
+	defer close(done)
+
+	tty := !s.Plain && envutil.IsConsole(s.Out)
+	interval := s.Interval
+	if !tty {
+		interval = s.LineInterval
+		_, _ = fmt.Fprintf(s.Out, "%s ...\n", s.Title())
+	}
+
+	// the ticker will panic on non-positive interval, fallback to the default
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+		if !tty {
+			interval = 5 * time.Second
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		if tty {
+			_, _ = fmt.Fprintf(s.Out, "%s%s %s", clearLine, s.frame(i), s.Title())
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !tty {
+				elapsed := int64(time.Since(s.started).Seconds())
+				_, _ = fmt.Fprintf(s.Out, "%s ... (%s)\n", s.Title(), HowLongAgo(elapsed))
+			}
+		}
+	}
+}
+
+// Stop the spinner, and print the final message if not empty.
+func (s *Spinner) Stop(finalMsg ...string) {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+
+	tty := !s.Plain && envutil.IsConsole(s.Out)
+	if tty {
+		_, _ = io.WriteString(s.Out, clearLine)
+	}
+
+	if len(finalMsg) > 0 && finalMsg[0] != "" {
+		_, _ = fmt.Fprintln(s.Out, finalMsg[0])
+	}
+}
+
+// get the frame by index, fallback to SpinnerLine on the Frames is empty
+func (s *Spinner) frame(i int) string {
+	frames := s.Frames
+	if len(frames) == 0 {
+		frames = SpinnerLine
+	}
+	return frames[i%len(frames)]
+}
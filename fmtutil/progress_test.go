@@ -0,0 +1,140 @@
+package fmtutil_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fmtutil"
+)
+
+func TestProgress(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	p := fmtutil.NewProgress(200, func(p *fmtutil.Progress) {
+		p.Title = "copying"
+		p.Out = buf
+		p.Width = 10
+	})
+
+	p.Start()
+	p.Add(50)
+	p.Advance()
+	is.Equal(int64(51), p.Current())
+	is.Equal(25.5, p.Percent())
+	is.True(p.Rate() > 0)
+	is.True(p.ETA() > 0)
+	is.True(strings.HasPrefix(p.String(), "copying  25.50% 51/200"))
+
+	// plain mode: only print first line in LineInterval
+	is.Equal(1, strings.Count(buf.String(), "\n"))
+
+	p.Set(100)
+	p.Finish()
+	is.Equal(int64(200), p.Current())
+	is.Equal(2, strings.Count(buf.String(), "\n"))
+	is.Contains(buf.String(), "copying 100.00% 200/200")
+
+	// finish again
+	p.Finish()
+	is.Equal(2, strings.Count(buf.String(), "\n"))
+}
+
+func TestProgress_proxy(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	p := fmtutil.NewProgress(2048, func(p *fmtutil.Progress) {
+		p.Out = buf
+		p.Bytes = true
+	})
+
+	n, err := io.Copy(ioutil.Discard, p.ProxyReader(strings.NewReader(strings.Repeat("a", 1024))))
+	is.NoError(err)
+	is.Equal(int64(1024), n)
+
+	_, err = p.ProxyWriter(ioutil.Discard).Write(make([]byte, 512))
+	is.NoError(err)
+	_, err = p.Write(make([]byte, 256))
+	is.NoError(err)
+	is.Equal(int64(1792), p.Current())
+	is.Contains(p.String(), "1.75K/2.00K")
+
+	// unknown total
+	p = fmtutil.NewProgress(0, func(p *fmtutil.Progress) {
+		p.Out = buf
+	})
+	p.Add(3)
+	is.Equal(float64(0), p.Percent())
+	is.Equal(time.Duration(-1), p.ETA())
+	is.True(strings.HasPrefix(p.String(), "3 "))
+	is.Contains(p.String(), "elapsed")
+}
+
+func TestSpinner(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	s := fmtutil.NewSpinner("waiting", func(s *fmtutil.Spinner) {
+		s.Out = buf
+		s.LineInterval = 20 * time.Millisecond
+	})
+
+	s.Start()
+	is.True(s.Running())
+	s.SetTitle("still waiting")
+	time.Sleep(50 * time.Millisecond)
+	s.Stop("done")
+	is.False(s.Running())
+
+	out := buf.String()
+	is.True(strings.HasSuffix(strings.SplitN(out, "\n", 2)[0], "waiting ..."))
+	is.Contains(out, "still waiting ... (")
+	is.True(strings.HasSuffix(out, "done\n"))
+
+	// stop again
+	s.Stop("done")
+	is.Equal(out, buf.String())
+
+	// non-positive interval will use the default
+	buf.Reset()
+	s.LineInterval = 0
+	is.NotPanics(func() {
+		s.Start()
+		s.Stop("ok")
+	})
+	is.Equal("still waiting ...\nok\n", buf.String())
+}
+
+func TestProgress_renderBar(t *testing.T) {
+	is := assert.New(t)
+
+	p := fmtutil.NewProgress(100, func(p *fmtutil.Progress) {
+		p.Width = 10
+	})
+	p.Set(50)
+	is.Equal("=====>    ", p.RenderBar())
+
+	// negative current
+	p.Set(-20)
+	is.Equal(">         ", p.RenderBar())
+
+	// default width
+	p.Width = 0
+	p.Set(100)
+	is.Equal(strings.Repeat("=", 40), p.RenderBar())
+}
+
+func TestSpinner_frame(t *testing.T) {
+	s := fmtutil.NewSpinner("waiting", func(s *fmtutil.Spinner) {
+		s.Frames = nil
+	})
+
+	assert.Equal(t, fmtutil.SpinnerLine[1], s.Frame(1))
+	assert.Equal(t, fmtutil.SpinnerLine[0], s.Frame(len(fmtutil.SpinnerLine)))
+}
@@ -0,0 +1,261 @@
+package fmtutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// time units for format duration and relative time.
+// NOTICE: the Month is 30 days and the Year is 365 days.
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+type timeUnit struct {
+	name string
+	dur  time.Duration
+}
+
+// time units, from largest to smallest.
+var timeUnits = []timeUnit{
+	{"year", Year},
+	{"month", Month},
+	{"week", Week},
+	{"day", Day},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// TimeLocale messages for format duration and relative time.
+type TimeLocale struct {
+	// Now message on the duration less than granularity. eg: "just now"
+	Now string
+	// Past format for past time. eg: "%s ago"
+	Past string
+	// Future format for future time. eg: "in %s"
+	Future string
+	// Sep separator between multi parts. eg: " "
+	Sep string
+	// NumSep separator between number and unit. eg: " "
+	NumSep string
+	// Units mapping unit name to {singular, plural} words.
+	// unit names: year, month, week, day, hour, minute, second
+	Units map[string][2]string
+	// ShortUnits mapping unit name to short word. eg: "h", "m"
+	ShortUnits map[string]string
+}
+
+// DefaultTimeLocale default locale name for format time
+var DefaultTimeLocale = "en"
+
+var timeLocales = map[string]*TimeLocale{
+	"en": {
+		Now:    "just now",
+		Past:   "%s ago",
+		Future: "in %s",
+		Sep:    " ",
+		NumSep: " ",
+		Units: map[string][2]string{
+			"year":   {"year", "years"},
+			"month":  {"month", "months"},
+			"week":   {"week", "weeks"},
+			"day":    {"day", "days"},
+			"hour":   {"hour", "hours"},
+			"minute": {"minute", "minutes"},
+			"second": {"second", "seconds"},
+		},
+		ShortUnits: map[string]string{
+			"year":   "y",
+			"month":  "mo",
+			"week":   "w",
+			"day":    "d",
+			"hour":   "h",
+			"minute": "m",
+			"second": "s",
+		},
+	},
+	"zh": {
+		Now:    "刚刚",
+		Past:   "%s前",
+		Future: "%s后",
+		Units: map[string][2]string{
+			"year":   {"年", "年"},
+			"month":  {"个月", "个月"},
+			"week":   {"周", "周"},
+			"day":    {"天", "天"},
+			"hour":   {"小时", "小时"},
+			"minute": {"分钟", "分钟"},
+			"second": {"秒", "秒"},
+		},
+		ShortUnits: map[string]string{
+			"year":   "年",
+			"month":  "月",
+			"week":   "周",
+			"day":    "天",
+			"hour":   "时",
+			"minute": "分",
+			"second": "秒",
+		},
+	},
+}
+
+// AddTimeLocale add or override a locale for format time.
+//
+// NOTICE: it is not concurrent safe, please call it on init.
+func AddTimeLocale(name string, locale *TimeLocale) {
+	timeLocales[name] = locale
+}
+
+// GetTimeLocale get locale by name, will fallback to DefaultTimeLocale.
+func GetTimeLocale(name string) *TimeLocale {
+	if l, ok := timeLocales[name]; ok {
+		return l
+	}
+	return timeLocales[DefaultTimeLocale]
+}
+
+// TimeFormatter for format duration and relative time.
+type TimeFormatter struct {
+	// Locale name. default is DefaultTimeLocale
+	Locale string
+	// Precision max number of units in the result. default is 1
+	//
+	// eg: Precision=2 "1 hour 5 minutes"
+	Precision int
+	// Granularity the smallest unit. default is time.Second
+	//
+	// eg: Granularity=time.Minute, 30s will be "just now"
+	Granularity time.Duration
+	// Short use short units. eg: "1h 5m"
+	Short bool
+}
+
+// NewTimeFormatter create
+func NewTimeFormatter(fns ...func(tf *TimeFormatter)) *TimeFormatter {
+	tf := &TimeFormatter{
+		Locale:      DefaultTimeLocale,
+		Precision:   1,
+		Granularity: time.Second,
+	}
+
+	for _, fn := range fns {
+		fn(tf)
+	}
+	return tf
+}
+
+// Duration format the duration. eg: "3 hours", "1h 5m"
+// return empty string on the duration less than Granularity.
+func (tf *TimeFormatter) Duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	l := GetTimeLocale(tf.Locale)
+	precision := tf.Precision
+	if precision < 1 {
+		precision = 1
+	}
+
+	parts := make([]string, 0, precision)
+	for _, u := range timeUnits {
+		if u.dur < tf.Granularity || len(parts) >= precision {
+			break
+		}
+
+		n := int64(d / u.dur)
+		if n == 0 {
+			continue
+		}
+
+		d -= time.Duration(n) * u.dur
+		parts = append(parts, tf.unitText(l, u.name, n))
+	}
+
+	return strings.Join(parts, l.Sep)
+}
+
+// the unit words will fallback to the DefaultTimeLocale on not found in the locale.
+func (tf *TimeFormatter) unitText(l *TimeLocale, unit string, n int64) string {
+	def := timeLocales[DefaultTimeLocale]
+	num := strconv.FormatInt(n, 10)
+	if tf.Short {
+		short, ok := l.ShortUnits[unit]
+		if !ok {
+			short = def.ShortUnits[unit]
+		}
+		return num + short
+	}
+
+	sep := l.NumSep
+	words, ok := l.Units[unit]
+	if !ok {
+		sep, words = def.NumSep, def.Units[unit]
+	}
+	if n == 1 {
+		return num + sep + words[0]
+	}
+	return num + sep + words[1]
+}
+
+// Relative format the time relative to the now. eg: "3 hours ago", "in 2 weeks"
+func (tf *TimeFormatter) Relative(t time.Time) string {
+	return tf.RelativeTo(t, time.Now())
+}
+
+// RelativeTo format the time relative to the base time.
+func (tf *TimeFormatter) RelativeTo(t, base time.Time) string {
+	d := t.Sub(base)
+	str := tf.Duration(d)
+
+	l := GetTimeLocale(tf.Locale)
+	if str == "" {
+		return l.Now
+	}
+
+	if d < 0 {
+		return fmt.Sprintf(l.Past, str)
+	}
+	return fmt.Sprintf(l.Future, str)
+}
+
+// FormatDuration format the duration friendly.
+//
+// Usage:
+// 	FormatDuration(65*time.Minute) // "1 hour"
+// 	FormatDuration(65*time.Minute, func(tf *TimeFormatter) {
+// 		tf.Precision = 2
+// 		tf.Short = true
+// 	}) // "1h 5m"
+func FormatDuration(d time.Duration, fns ...func(tf *TimeFormatter)) string {
+	tf := NewTimeFormatter(fns...)
+	if str := tf.Duration(d); str != "" {
+		return str
+	}
+
+	// less than granularity. eg: "0 seconds"
+	for _, u := range timeUnits {
+		if u.dur <= tf.Granularity {
+			return tf.unitText(GetTimeLocale(tf.Locale), u.name, 0)
+		}
+	}
+	return "0"
+}
+
+// RelativeTime format the time relative to now, both past and future.
+//
+// Usage:
+// 	RelativeTime(time.Now().Add(-3 * time.Hour)) // "3 hours ago"
+// 	RelativeTime(time.Now().Add(50 * time.Hour)) // "in 2 days"
+// 	RelativeTime(time.Now().Add(-3 * time.Hour), func(tf *TimeFormatter) {
+// 		tf.Locale = "zh"
+// 	}) // "3小时前"
+func RelativeTime(t time.Time, fns ...func(tf *TimeFormatter)) string {
+	return NewTimeFormatter(fns...).Relative(t)
+}
@@ -0,0 +1,87 @@
+package fmtutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fmtutil"
+)
+
+func TestFormatDuration(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("1 hour", fmtutil.FormatDuration(65*time.Minute))
+	is.Equal("2 weeks", fmtutil.FormatDuration(15*fmtutil.Day))
+	is.Equal("1 year", fmtutil.FormatDuration(-400*fmtutil.Day))
+	is.Equal("0 seconds", fmtutil.FormatDuration(time.Millisecond))
+	is.Equal("0 minutes", fmtutil.FormatDuration(time.Second, func(tf *fmtutil.TimeFormatter) {
+		tf.Granularity = time.Minute
+	}))
+
+	is.Equal("1h 5m", fmtutil.FormatDuration(65*time.Minute+3*time.Second, func(tf *fmtutil.TimeFormatter) {
+		tf.Precision = 2
+		tf.Short = true
+	}))
+	is.Equal("1 day 1 hour 5 minutes", fmtutil.FormatDuration(fmtutil.Day+65*time.Minute+3*time.Second, func(tf *fmtutil.TimeFormatter) {
+		tf.Precision = 3
+	}))
+	is.Equal("1 day 5 minutes", fmtutil.FormatDuration(fmtutil.Day+5*time.Minute+3*time.Second, func(tf *fmtutil.TimeFormatter) {
+		tf.Precision = 2
+		tf.Granularity = time.Minute
+	}))
+	is.Equal("1小时5分钟", fmtutil.FormatDuration(65*time.Minute, func(tf *fmtutil.TimeFormatter) {
+		tf.Precision = 2
+		tf.Locale = "zh"
+	}))
+}
+
+func TestRelativeTime(t *testing.T) {
+	is := assert.New(t)
+	base := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tf := fmtutil.NewTimeFormatter()
+	is.Equal("3 hours ago", tf.RelativeTo(base.Add(-3*time.Hour), base))
+	is.Equal("in 3 hours", tf.RelativeTo(base.Add(3*time.Hour), base))
+	is.Equal("2 weeks ago", tf.RelativeTo(base.Add(-15*fmtutil.Day), base))
+	is.Equal("in 2 months", tf.RelativeTo(base.Add(65*fmtutil.Day), base))
+	is.Equal("just now", tf.RelativeTo(base.Add(-time.Millisecond), base))
+
+	tf = fmtutil.NewTimeFormatter(func(tf *fmtutil.TimeFormatter) {
+		tf.Locale = "zh"
+		tf.Granularity = time.Minute
+	})
+	is.Equal("3小时前", tf.RelativeTo(base.Add(-3*time.Hour), base))
+	is.Equal("2天后", tf.RelativeTo(base.Add(50*time.Hour), base))
+	is.Equal("刚刚", tf.RelativeTo(base.Add(-30*time.Second), base))
+
+	is.Equal("1 hour ago", fmtutil.RelativeTime(time.Now().Add(-61*time.Minute)))
+
+	// custom and fallback locale
+	t.Cleanup(func() {
+		fmtutil.RemoveTimeLocale("test")
+	})
+	fmtutil.AddTimeLocale("test", &fmtutil.TimeLocale{
+		Now:    "now",
+		Past:   "-%s",
+		Future: "+%s",
+		ShortUnits: map[string]string{
+			"hour": "H",
+		},
+	})
+	is.Equal("-3H", fmtutil.RelativeTime(time.Now().Add(-3*time.Hour-time.Second), func(tf *fmtutil.TimeFormatter) {
+		tf.Locale = "test"
+		tf.Short = true
+	}))
+	// fallback to the default locale unit words
+	is.Equal("-3 hours", fmtutil.RelativeTime(time.Now().Add(-3*time.Hour-time.Second), func(tf *fmtutil.TimeFormatter) {
+		tf.Locale = "test"
+	}))
+	is.Equal("-2m", fmtutil.RelativeTime(time.Now().Add(-2*time.Minute-time.Second), func(tf *fmtutil.TimeFormatter) {
+		tf.Locale = "test"
+		tf.Short = true
+	}))
+	is.Equal("3 hours ago", fmtutil.RelativeTime(time.Now().Add(-3*time.Hour-time.Second), func(tf *fmtutil.TimeFormatter) {
+		tf.Locale = "not-exist"
+	}))
+}
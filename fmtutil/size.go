@@ -0,0 +1,200 @@
+package fmtutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// data size unit styles
+const (
+	// SizeUnitShort single letter unit. eg: "K", "M"
+	// the single letter means binary on parse, so the SI size will use the "B" suffix unit.
+	SizeUnitShort uint8 = iota
+	// SizeUnitBytes unit with "B" suffix. eg: "kB", "MB"
+	// the "B" suffix means SI on parse, so the binary size will use the IEC unit.
+	SizeUnitBytes
+	// SizeUnitIEC IEC binary unit. eg: "KiB", "MiB". will always use 1024 as base.
+	SizeUnitIEC
+)
+
+// unit prefixes of the data size, index is the exponent.
+const sizePrefixes = "KMGTPE"
+
+// SizeFormatter for format bytes number to human-readable data size.
+type SizeFormatter struct {
+	// Precision the decimal places. default is 2
+	Precision int
+	// Binary use 1024 as base, otherwise use 1000(SI).
+	Binary bool
+	// UnitStyle the unit style. see SizeUnitShort, SizeUnitBytes, SizeUnitIEC
+	UnitStyle uint8
+	// Space add a space between number and unit. eg: "1.50 KB"
+	Space bool
+	// TrimZero trim the tail zeros of decimal. eg: "1.50K" -> "1.5K"
+	TrimZero bool
+}
+
+// DefaultSizeFormatter the default formatter, use for DataSize()
+var DefaultSizeFormatter = NewSizeFormatter()
+
+// NewSizeFormatter create, default is binary base and short unit style.
+//
+// Usage:
+// 	sf := NewSizeFormatter(func(sf *SizeFormatter) {
+// 		sf.UnitStyle = SizeUnitIEC
+// 		sf.Space = true
+// 	})
+// 	sf.Format(1536) // "1.50 KiB"
+func NewSizeFormatter(fns ...func(sf *SizeFormatter)) *SizeFormatter {
+	sf := &SizeFormatter{
+		Precision: 2,
+		Binary:    true,
+	}
+
+	for _, fn := range fns {
+		fn(sf)
+	}
+	return sf
+}
+
+// Format bytes number to data size string.
+func (sf *SizeFormatter) Format(bytes uint64) string {
+	base := float64(1000)
+	if sf.Binary || sf.UnitStyle == SizeUnitIEC {
+		base = 1024
+	}
+
+	sep := ""
+	if sf.Space {
+		sep = " "
+	}
+
+	if float64(bytes) < base {
+		return strconv.FormatUint(bytes, 10) + sep + "B"
+	}
+
+	val := float64(bytes) / base
+	exp := 0
+	for val >= base && exp < len(sizePrefixes)-1 {
+		val /= base
+		exp++
+	}
+
+	num := strconv.FormatFloat(val, 'f', sf.Precision, 64)
+
+	// rounding up to next unit. eg: 1048575 -> "1024.00K" -> "1.00M"
+	if rounded, _ := strconv.ParseFloat(num, 64); rounded >= base && exp < len(sizePrefixes)-1 {
+		exp++
+		num = strconv.FormatFloat(val/base, 'f', sf.Precision, 64)
+	}
+
+	if sf.TrimZero && strings.IndexByte(num, '.') > 0 {
+		num = strings.TrimRight(strings.TrimRight(num, "0"), ".")
+	}
+	return num + sep + sf.unit(exp)
+}
+
+// get the unit, which can be parsed back to same base by ParseDataSize()
+func (sf *SizeFormatter) unit(exp int) string {
+	prefix := sizePrefixes[exp : exp+1]
+	if sf.Binary || sf.UnitStyle == SizeUnitIEC {
+		if sf.UnitStyle == SizeUnitShort {
+			return prefix
+		}
+		return prefix + "iB"
+	}
+
+	if prefix == "K" {
+		return "kB" // SI symbol of kilo is lowercase
+	}
+	return prefix + "B"
+}
+
+// FormatSize format bytes number by the SizeFormatter options.
+//
+// Usage:
+// 	FormatSize(1500, func(sf *SizeFormatter) {
+// 		sf.Binary = false
+// 		sf.UnitStyle = SizeUnitBytes
+// 	}) // "1.50kB"
+func FormatSize(bytes uint64, fns ...func(sf *SizeFormatter)) string {
+	return NewSizeFormatter(fns...).Format(bytes)
+}
+
+// ParseDataSize parse human-readable data size string to bytes number.
+// the unit is case-insensitive, and allow spaces between number and unit.
+//
+// Unit rules:
+// 	- IEC unit use 1024 as base. eg: "KiB", "MiB", "Ki"
+// 	- SI unit with "B" use 1000 as base. eg: "KB", "MB"
+// 	- single letter unit use 1024 as base, same as DataSize() output. eg: "K", "M"
+//
+// Usage:
+// 	ParseDataSize("1.5GB") // 1500000000
+// 	ParseDataSize("512KiB") // 524288
+// 	ParseDataSize("10 M") // 10485760
+func ParseDataSize(s string) (uint64, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, fmt.Errorf("fmtutil: invalid data size %q", s)
+	}
+
+	// split number and unit
+	pos := strings.IndexFunc(str, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+
+	numStr, unit := str, ""
+	if pos >= 0 {
+		numStr, unit = str[:pos], strings.TrimSpace(str[pos:])
+	}
+
+	num, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("fmtutil: invalid data size %q", s)
+	}
+
+	mul, ok := sizeUnitMultiple(unit)
+	if !ok {
+		return 0, fmt.Errorf("fmtutil: invalid data size unit %q", unit)
+	}
+
+	val := num * mul
+	// float64(math.MaxUint64) is rounded up to 2^64
+	if val >= math.MaxUint64 {
+		return 0, fmt.Errorf("fmtutil: data size %q is overflow", s)
+	}
+	return uint64(val + 0.5), nil
+}
+
+// MustParseDataSize parse data size string, will panic on error
+func MustParseDataSize(s string) uint64 {
+	size, err := ParseDataSize(s)
+	if err != nil {
+		panic(err)
+	}
+	return size
+}
+
+func sizeUnitMultiple(unit string) (float64, bool) {
+	unit = strings.ToUpper(unit)
+	if unit == "" || unit == "B" {
+		return 1, true
+	}
+
+	exp := strings.IndexByte(sizePrefixes, unit[0]) + 1
+	if exp == 0 {
+		return 0, false
+	}
+
+	switch unit[1:] {
+	case "", "I", "IB": // "K", "Ki", "KiB"
+		return math.Pow(1024, float64(exp)), true
+	case "B": // "KB"
+		return math.Pow(1000, float64(exp)), true
+	}
+	return 0, false
+}
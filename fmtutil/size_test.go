@@ -0,0 +1,88 @@
+package fmtutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fmtutil"
+)
+
+func TestSizeFormatter(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("1.00T", fmtutil.DataSize(1<<40))
+	is.Equal("1.00M", fmtutil.DataSize(1048575))
+	is.Equal("1023.99K", fmtutil.DataSize(1048570))
+	is.Equal("1.00MB", fmtutil.FormatSize(999999, func(sf *fmtutil.SizeFormatter) {
+		sf.Binary = false
+		sf.UnitStyle = fmtutil.SizeUnitBytes
+	}))
+	is.Equal("1.50 KiB", fmtutil.FormatSize(1536, func(sf *fmtutil.SizeFormatter) {
+		sf.UnitStyle = fmtutil.SizeUnitIEC
+		sf.Space = true
+	}))
+	is.Equal("1.5kB", fmtutil.FormatSize(1500, func(sf *fmtutil.SizeFormatter) {
+		sf.Binary = false
+		sf.UnitStyle = fmtutil.SizeUnitBytes
+		sf.TrimZero = true
+	}))
+	is.Equal("2MiB", fmtutil.FormatSize(2*1024*1024, func(sf *fmtutil.SizeFormatter) {
+		sf.UnitStyle = fmtutil.SizeUnitBytes
+		sf.Precision = 0
+	}))
+	is.Equal("999B", fmtutil.FormatSize(999, func(sf *fmtutil.SizeFormatter) {
+		sf.Binary = false
+	}))
+	is.Equal("1.50kB", fmtutil.FormatSize(1500, func(sf *fmtutil.SizeFormatter) {
+		sf.Binary = false
+	}))
+
+	sf := fmtutil.NewSizeFormatter()
+	is.Equal(fmtutil.DataSize(346778), sf.Format(346778))
+
+	// the output can be parsed back
+	for _, binary := range []bool{true, false} {
+		for _, style := range []uint8{fmtutil.SizeUnitShort, fmtutil.SizeUnitBytes, fmtutil.SizeUnitIEC} {
+			str := fmtutil.FormatSize(1536, func(sf *fmtutil.SizeFormatter) {
+				sf.Binary = binary
+				sf.UnitStyle = style
+				sf.Precision = 3
+			})
+			is.Equal(uint64(1536), fmtutil.MustParseDataSize(str), str)
+		}
+	}
+}
+
+func TestParseDataSize(t *testing.T) {
+	tests := []struct {
+		args string
+		want uint64
+	}{
+		{"346", 346},
+		{"346B", 346},
+		{"1.5GB", 1500000000},
+		{"1.5 gb", 1500000000},
+		{"512KiB", 512 * 1024},
+		{"512Ki", 512 * 1024},
+		{"10 M", 10 * 1024 * 1024},
+		{"2kB", 2000},
+		{"1T", 1 << 40},
+		{" 3.39K ", 3471},
+	}
+
+	for _, tt := range tests {
+		got, err := fmtutil.ParseDataSize(tt.args)
+		assert.NoError(t, err, tt.args)
+		assert.Equal(t, tt.want, got, tt.args)
+	}
+
+	for _, s := range []string{"", "abc", "1.2.3K", "12X", "12KX", "1000000E", "16EiB", "18446744073709551616"} {
+		_, err := fmtutil.ParseDataSize(s)
+		assert.Error(t, err, s)
+	}
+
+	assert.Equal(t, uint64(1024), fmtutil.MustParseDataSize("1K"))
+	assert.Panics(t, func() {
+		fmtutil.MustParseDataSize("invalid")
+	})
+}
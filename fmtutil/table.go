@@ -0,0 +1,438 @@
+package fmtutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urionz/color"
+	"github.com/urionz/goutil/envutil"
+	"golang.org/x/term"
+)
+
+// align position for text
+const (
+	AlignLeft uint8 = iota
+	AlignCenter
+	AlignRight
+)
+
+// TableStyle border chars and render style for the Table
+type TableStyle struct {
+	Name string
+	// Top border chars of the top line: left, line, cross, right.
+	// will not render the line on the line char is empty.
+	Top [4]string
+	// Head border chars of the header separator line: left, line, cross, right
+	Head [4]string
+	// Bottom border chars of the bottom line: left, line, cross, right
+	Bottom [4]string
+	// Row border chars of the row: left, separator, right
+	Row [3]string
+	// Padding spaces number on the cell left and right
+	Padding int
+	// AlignMark mark column align by ":" in the header separator line. use for markdown
+	AlignMark bool
+	// Delimiter render as delimited text on not zero, eg: ',' for CSV
+	Delimiter rune
+}
+
+// builtin table styles
+var (
+	TableASCII = &TableStyle{
+		Name:    "ascii",
+		Top:     [4]string{"+", "-", "+", "+"},
+		Head:    [4]string{"+", "-", "+", "+"},
+		Bottom:  [4]string{"+", "-", "+", "+"},
+		Row:     [3]string{"|", "|", "|"},
+		Padding: 1,
+	}
+	TableUnicode = &TableStyle{
+		Name:    "unicode",
+		Top:     [4]string{"┌", "─", "┬", "┐"},
+		Head:    [4]string{"├", "─", "┼", "┤"},
+		Bottom:  [4]string{"└", "─", "┴", "┘"},
+		Row:     [3]string{"│", "│", "│"},
+		Padding: 1,
+	}
+	TableMarkdown = &TableStyle{
+		Name:      "markdown",
+		Head:      [4]string{"|", "-", "|", "|"},
+		Row:       [3]string{"|", "|", "|"},
+		Padding:   1,
+		AlignMark: true,
+	}
+	TableSimple = &TableStyle{
+		Name: "simple",
+		Head: [4]string{"", "-", "  ", ""},
+		Row:  [3]string{"", "  ", ""},
+	}
+	TableCSV = &TableStyle{Name: "csv", Delimiter: ','}
+	TableTSV = &TableStyle{Name: "tsv", Delimiter: '\t'}
+)
+
+// TableColumn settings
+type TableColumn struct {
+	Title string
+	// Align for the column cells. default is AlignLeft
+	Align uint8
+	// MaxWidth max display width of the column, 0 is no limit.
+	MaxWidth int
+	// Wrap the long text to multi lines, otherwise will truncate it.
+	Wrap bool
+	// Style color style for the column cells
+	Style color.Style
+}
+
+// Table for render tabular data on terminal.
+//
+// Usage:
+// 	t := fmtutil.NewTable("Name", "Age")
+// 	t.AddRow("inhere", 23)
+// 	t.AddRow("tom", 22)
+// 	t.Print()
+type Table struct {
+	// Style border style. default is TableASCII
+	Style *TableStyle
+	// NoColor disable color render.
+	// will auto disable on the writer is not a terminal, or envutil.IsSupportColor() returns false.
+	NoColor bool
+	// HeadStyle color style for the header cells. default is bold
+	HeadStyle color.Style
+	// BorderStyle color style for the border chars
+	BorderStyle color.Style
+	// Ellipsis the tail text for truncated cells. default is "..."
+	Ellipsis string
+
+	cols []*TableColumn
+	rows [][]string
+	// has header titles
+	hasHead bool
+}
+
+// NewTable create table with header titles
+func NewTable(titles ...string) *Table {
+	t := &Table{
+		Style:     TableASCII,
+		HeadStyle: color.Style{color.OpBold},
+		Ellipsis:  "...",
+	}
+
+	return t.SetHeader(titles...)
+}
+
+// SetHeader set header titles
+func (t *Table) SetHeader(titles ...string) *Table {
+	t.ensureCols(len(titles))
+	for i, title := range titles {
+		t.cols[i].Title = title
+	}
+
+	t.hasHead = len(titles) > 0
+	return t
+}
+
+// SetStyle set border style
+func (t *Table) SetStyle(style *TableStyle) *Table {
+	t.Style = style
+	return t
+}
+
+// Column get column setting by index, will auto create if not exists.
+//
+// Usage:
+// 	t.Column(1).Align = fmtutil.AlignRight
+func (t *Table) Column(idx int) *TableColumn {
+	t.ensureCols(idx + 1)
+	return t.cols[idx]
+}
+
+// SetAlign set align for the columns
+func (t *Table) SetAlign(align uint8, idxes ...int) *Table {
+	for _, idx := range idxes {
+		t.Column(idx).Align = align
+	}
+	return t
+}
+
+// SetMaxWidth set max width for the columns, if not set idxes, will apply to all columns.
+func (t *Table) SetMaxWidth(width int, wrap bool, idxes ...int) *Table {
+	if len(idxes) == 0 {
+		for i := range t.cols {
+			idxes = append(idxes, i)
+		}
+	}
+
+	for _, idx := range idxes {
+		col := t.Column(idx)
+		col.MaxWidth = width
+		col.Wrap = wrap
+	}
+	return t
+}
+
+// AddRow add a row data
+func (t *Table) AddRow(cells ...interface{}) *Table {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i] = fmt.Sprint(cell)
+	}
+
+	t.ensureCols(len(row))
+	t.rows = append(t.rows, row)
+	return t
+}
+
+// AddRows add multi rows data
+func (t *Table) AddRows(rows [][]string) *Table {
+	for _, row := range rows {
+		t.ensureCols(len(row))
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+func (t *Table) ensureCols(n int) {
+	for len(t.cols) < n {
+		t.cols = append(t.cols, &TableColumn{})
+	}
+}
+
+// String render table to string, without color
+func (t *Table) String() string {
+	buf := new(bytes.Buffer)
+	_ = t.Render(buf)
+	return buf.String()
+}
+
+// Print render table to os.Stdout
+func (t *Table) Print() {
+	_ = t.Render(os.Stdout)
+}
+
+// Render table to the writer
+func (t *Table) Render(w io.Writer) error {
+	style := t.Style
+	if style == nil {
+		style = TableASCII
+	}
+
+	if style.Delimiter != 0 {
+		return t.renderDelimited(w, style.Delimiter)
+	}
+
+	r := &tableRenderer{Table: t, style: style}
+	r.useColor = !t.NoColor && isTerminal(w) && envutil.IsSupportColor()
+	r.pad = strings.Repeat(" ", style.Padding)
+	r.calcWidths()
+
+	buf := new(bytes.Buffer)
+	r.renderLine(buf, style.Top, false)
+	if t.hasHead {
+		titles := make([]string, len(t.cols))
+		for i, col := range t.cols {
+			titles[i] = col.Title
+		}
+
+		r.renderRow(buf, titles, true)
+		r.renderLine(buf, style.Head, style.AlignMark)
+	}
+
+	for _, row := range t.rows {
+		r.renderRow(buf, row, false)
+	}
+	r.renderLine(buf, style.Bottom, false)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func (t *Table) renderDelimited(w io.Writer, delimiter rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+
+	if t.hasHead {
+		titles := make([]string, len(t.cols))
+		for i, col := range t.cols {
+			titles[i] = col.Title
+		}
+
+		if err := cw.Write(titles); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range t.rows {
+		cells := make([]string, len(t.cols))
+		for i := range row {
+			cells[i] = ClearColor(row[i])
+		}
+
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+type tableRenderer struct {
+	*Table
+	style    *TableStyle
+	useColor bool
+	pad      string
+	widths   []int
+}
+
+func (r *tableRenderer) calcWidths() {
+	r.widths = make([]int, len(r.cols))
+	for i, col := range r.cols {
+		if r.hasHead {
+			r.widths[i] = maxLineWidth(col.Title)
+		}
+
+		for _, row := range r.rows {
+			if i < len(row) {
+				if w := maxLineWidth(row[i]); w > r.widths[i] {
+					r.widths[i] = w
+				}
+			}
+		}
+
+		if col.MaxWidth > 0 && r.widths[i] > col.MaxWidth {
+			r.widths[i] = col.MaxWidth
+		}
+	}
+}
+
+func maxLineWidth(s string) (max int) {
+	for _, line := range strings.Split(s, "\n") {
+		if w := TextWidth(line); w > max {
+			max = w
+		}
+	}
+	return
+}
+
+func (r *tableRenderer) colorize(s string, style color.Style) string {
+	if !r.useColor || len(style) == 0 || s == "" {
+		return s
+	}
+	return fmt.Sprintf(color.FullColorTpl, style.Code(), s)
+}
+
+// split cell text to lines by column width
+func (r *tableRenderer) cellLines(col *TableColumn, text string, width int) []string {
+	if !r.useColor {
+		text = ClearColor(text)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case TextWidth(line) <= width:
+			lines = append(lines, line)
+		case col.Wrap:
+			lines = append(lines, WrapText(line, width)...)
+		default:
+			lines = append(lines, TruncateText(line, width, r.Ellipsis))
+		}
+	}
+	return lines
+}
+
+func (r *tableRenderer) renderRow(buf *bytes.Buffer, row []string, isHead bool) {
+	cells := make([][]string, len(r.cols))
+
+	height := 1
+	for i, col := range r.cols {
+		var text string
+		if i < len(row) {
+			text = row[i]
+		}
+
+		cells[i] = r.cellLines(col, text, r.widths[i])
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+
+	border := r.style.Row
+	for ln := 0; ln < height; ln++ {
+		buf.WriteString(r.colorize(border[0], r.BorderStyle))
+
+		for i, col := range r.cols {
+			if i > 0 {
+				buf.WriteString(r.colorize(border[1], r.BorderStyle))
+			}
+
+			var text string
+			if ln < len(cells[i]) {
+				text = cells[i][ln]
+			}
+
+			align, cellStyle := col.Align, col.Style
+			if isHead {
+				cellStyle = r.HeadStyle
+			}
+
+			// padding first, then render color
+			padded := PadText(text, r.widths[i], align)
+			if trimmed := strings.TrimSpace(padded); trimmed != "" {
+				padded = strings.Replace(padded, trimmed, r.colorize(trimmed, cellStyle), 1)
+			}
+
+			// don't add tail spaces for the last column on no right border
+			if i == len(r.cols)-1 && border[2] == "" {
+				buf.WriteString(strings.TrimRight(r.pad+padded, " "))
+			} else {
+				buf.WriteString(r.pad + padded + r.pad)
+			}
+		}
+
+		buf.WriteString(r.colorize(border[2], r.BorderStyle))
+		buf.WriteByte('\n')
+	}
+}
+
+func (r *tableRenderer) renderLine(buf *bytes.Buffer, chars [4]string, alignMark bool) {
+	if chars[1] == "" {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(chars[0])
+	for i, w := range r.widths {
+		if i > 0 {
+			sb.WriteString(chars[2])
+		}
+
+		n := w + 2*r.style.Padding
+		line := strings.Repeat(chars[1], n)
+		if alignMark && n > 1 {
+			switch r.cols[i].Align {
+			case AlignCenter:
+				line = ":" + strings.Repeat(chars[1], n-2) + ":"
+			case AlignRight:
+				line = strings.Repeat(chars[1], n-1) + ":"
+			default:
+				line = ":" + strings.Repeat(chars[1], n-1)
+			}
+		}
+		sb.WriteString(line)
+	}
+	sb.WriteString(chars[3])
+
+	buf.WriteString(r.colorize(sb.String(), r.BorderStyle))
+	buf.WriteByte('\n')
+}
+
+// check the writer is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
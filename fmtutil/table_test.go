@@ -0,0 +1,107 @@
+package fmtutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fmtutil"
+)
+
+func newTestTable() *fmtutil.Table {
+	t := fmtutil.NewTable("Name", "Age", "City")
+	t.NoColor = true
+	t.AddRow("inhere", 23, "深圳")
+	t.AddRow("tom", 2, "New York")
+	return t
+}
+
+func TestTable_styles(t *testing.T) {
+	is := assert.New(t)
+
+	tb := newTestTable().SetAlign(fmtutil.AlignRight, 1)
+	is.Equal(`+--------+-----+----------+
+| Name   | Age | City     |
++--------+-----+----------+
+| inhere |  23 | 深圳     |
+| tom    |   2 | New York |
++--------+-----+----------+
+`, tb.String())
+
+	tb.SetStyle(fmtutil.TableUnicode)
+	is.Equal(`┌────────┬─────┬──────────┐
+│ Name   │ Age │ City     │
+├────────┼─────┼──────────┤
+│ inhere │  23 │ 深圳     │
+│ tom    │   2 │ New York │
+└────────┴─────┴──────────┘
+`, tb.String())
+
+	tb.SetStyle(fmtutil.TableMarkdown).SetAlign(fmtutil.AlignCenter, 2)
+	is.Equal(`| Name   | Age |   City   |
+|:-------|----:|:--------:|
+| inhere |  23 |   深圳   |
+| tom    |   2 | New York |
+`, tb.String())
+
+	tb.SetStyle(fmtutil.TableSimple)
+	is.Equal(`Name    Age    City
+------  ---  --------
+inhere   23    深圳
+tom       2  New York
+`, tb.String())
+
+	tb.SetStyle(fmtutil.TableCSV)
+	is.Equal("Name,Age,City\ninhere,23,深圳\ntom,2,New York\n", tb.String())
+
+	tb.SetStyle(fmtutil.TableTSV)
+	is.Equal("Name\tAge\tCity\ninhere\t23\t深圳\ntom\t2\tNew York\n", tb.String())
+}
+
+func TestTable_maxWidth(t *testing.T) {
+	is := assert.New(t)
+
+	tb := fmtutil.NewTable()
+	tb.NoColor = true
+	tb.AddRow("id", "some long description text")
+	tb.Column(1).MaxWidth = 10
+
+	is.Equal(`+----+------------+
+| id | some lo... |
++----+------------+
+`, tb.String())
+
+	tb.SetMaxWidth(10, true, 1)
+	is.Equal(`+----+------------+
+| id | some long  |
+|    | descriptio |
+|    | n text     |
++----+------------+
+`, tb.String())
+}
+
+func TestTextWidth(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(3, fmtutil.TextWidth("abc"))
+	is.Equal(4, fmtutil.TextWidth("你好"))
+	is.Equal(4, fmtutil.TextWidth("ｈｉ"))
+	is.Equal(3, fmtutil.TextWidth("\x1b[1;32mabc\x1b[0m"))
+	is.Equal(1, fmtutil.TextWidth("é"))
+
+	is.Equal("你...", fmtutil.TruncateText("你好世界", 5, "..."))
+	is.Equal("你好", fmtutil.TruncateText("你好世界", 4, ""))
+	is.Equal([]string{"你好", "世界"}, fmtutil.WrapText("你好世界", 5))
+	is.Equal([]string{"hello", "world"}, fmtutil.WrapText("hello world", 6))
+	is.Equal(" ab ", fmtutil.PadText("ab", 4, fmtutil.AlignCenter))
+}
+
+func TestTable_noColorOnNotTerminal(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+
+	tb := fmtutil.NewTable("Name")
+	tb.AddRow("\x1b[32minhere\x1b[0m")
+
+	str := tb.String()
+	assert.NotContains(t, str, "\x1b[")
+	assert.Contains(t, str, "| inhere |")
+}
@@ -0,0 +1,226 @@
+package fmtutil
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// match color codes. eg "\033[1;36mText\x1b[0m"
+var colorCodeRegex = regexp.MustCompile(`\x1b\[[\d;?]*m`)
+
+// the East Asian Wide and Fullwidth unicode ranges
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, // Hangul Jamo
+	{0x231A, 0x231B},
+	{0x2329, 0x232A},
+	{0x23E9, 0x23EC},
+	{0x23F0, 0x23F0},
+	{0x23F3, 0x23F3},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26F2, 0x26F5},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50},
+	{0x2E80, 0x303E},   // CJK Radicals, Kangxi, CJK Symbols
+	{0x3041, 0x33FF},   // Hiragana, Katakana, Bopomofo, CJK Compatibility
+	{0x3400, 0x4DBF},   // CJK Unified Ideographs Extension A
+	{0x4E00, 0x9FFF},   // CJK Unified Ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xA960, 0xA97F},   // Hangul Jamo Extended-A
+	{0xAC00, 0xD7A3},   // Hangul Syllables
+	{0xF900, 0xFAFF},   // CJK Compatibility Ideographs
+	{0xFE10, 0xFE19},   // Vertical forms
+	{0xFE30, 0xFE6F},   // CJK Compatibility Forms, Small Form Variants
+	{0xFF00, 0xFF60},   // Fullwidth Forms
+	{0xFFE0, 0xFFE6},   // Fullwidth Signs
+	{0x16FE0, 0x18AFF}, // Tangut
+	{0x1B000, 0x1B2FF}, // Kana Supplement
+	{0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A},
+	{0x1F200, 0x1F251},
+	{0x1F300, 0x1F64F}, // Misc Symbols and Pictographs, Emoticons
+	{0x1F680, 0x1F6FF}, // Transport and Map
+	{0x1F7E0, 0x1F7EB},
+	{0x1F900, 0x1F9FF}, // Supplemental Symbols and Pictographs
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD}, // CJK Unified Ideographs Extension B..F
+	{0x30000, 0x3FFFD}, // CJK Unified Ideographs Extension G
+}
+
+// RuneWidth get the display width of the rune in terminal.
+// East Asian wide chars is 2, combining and control chars is 0.
+func RuneWidth(r rune) int {
+	if r == 0 || r < 32 || (r >= 0x7f && r < 0xa0) {
+		return 0
+	}
+	if r < 0x1100 {
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			return 0
+		}
+		return 1
+	}
+
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			break
+		}
+		if r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// ClearColor clear the ANSI color codes in the string.
+func ClearColor(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+	return colorCodeRegex.ReplaceAllString(s, "")
+}
+
+// TextWidth get the display width of the string in terminal.
+// will ignore ANSI color codes.
+//
+// Usage:
+// 	TextWidth("abc") // 3
+// 	TextWidth("你好") // 4
+func TextWidth(s string) (width int) {
+	for _, r := range ClearColor(s) {
+		width += RuneWidth(r)
+	}
+	return
+}
+
+// TruncateText truncate the string to the display width, the tail
+// will be appended on truncated. eg: "..."
+//
+// NOTICE: the ANSI color codes in the string will be cleared.
+func TruncateText(s string, width int, tail string) string {
+	s = ClearColor(s)
+	if TextWidth(s) <= width {
+		return s
+	}
+
+	tailWidth := TextWidth(tail)
+	if tailWidth >= width {
+		tail, tailWidth = "", 0
+	}
+
+	return cutText(s, width-tailWidth) + tail
+}
+
+// cut the string to the max display width
+func cutText(s string, width int) string {
+	var w int
+	for i, r := range s {
+		rw := RuneWidth(r)
+		if w+rw > width {
+			return s[:i]
+		}
+		w += rw
+	}
+	return s
+}
+
+// WrapText wrap the string to multi lines by the display width.
+// will break at spaces as possible, and long words will be hard broken.
+//
+// NOTICE: the ANSI color codes in the string will be cleared.
+func WrapText(s string, width int) []string {
+	s = ClearColor(s)
+	if width <= 0 {
+		return strings.Split(s, "\n")
+	}
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, wrapLine(line, width)...)
+	}
+	return lines
+}
+
+func wrapLine(line string, width int) []string {
+	if TextWidth(line) <= width {
+		return []string{line}
+	}
+
+	var lines []string
+	var cur strings.Builder
+	var curWidth int
+
+	flush := func() {
+		lines = append(lines, strings.TrimRight(cur.String(), " "))
+		cur.Reset()
+		curWidth = 0
+	}
+
+	for _, word := range strings.SplitAfter(line, " ") {
+		ww := TextWidth(strings.TrimRight(word, " "))
+		if curWidth > 0 && curWidth+ww > width {
+			flush()
+		}
+
+		// hard break long word
+		for TextWidth(word) > width && ww > width {
+			part := cutText(word, width)
+			if part == "" { // first rune is wider than the width
+				_, size := utf8.DecodeRuneInString(word)
+				part = word[:size]
+			}
+
+			cur.WriteString(part)
+			flush()
+			word = word[len(part):]
+			ww = TextWidth(strings.TrimRight(word, " "))
+		}
+
+		cur.WriteString(word)
+		curWidth += TextWidth(word)
+	}
+
+	if cur.Len() > 0 {
+		flush()
+	}
+	return lines
+}
+
+// PadText padding the string to the display width by spaces.
+//
+// Usage:
+// 	PadText("ab", 4, AlignLeft) // "ab  "
+// 	PadText("ab", 4, AlignRight) // "  ab"
+func PadText(s string, width int, align uint8) string {
+	n := width - TextWidth(s)
+	if n <= 0 {
+		return s
+	}
+
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", n) + s
+	case AlignCenter:
+		left := n / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", n-left)
+	}
+	return s + strings.Repeat(" ", n)
+}
@@ -0,0 +1,380 @@
+package fsutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// supported hash algorithms
+const (
+	AlgoMD5    = "md5"
+	AlgoSHA1   = "sha1"
+	AlgoSHA256 = "sha256"
+	AlgoSHA512 = "sha512"
+	AlgoCRC32  = "crc32"
+)
+
+// NewHash create hash.Hash by algorithm name. eg: "sha256"
+func NewHash(algo string) (hash.Hash, error) {
+	switch strings.ToLower(algo) {
+	case AlgoMD5:
+		return md5.New(), nil
+	case AlgoSHA1:
+		return sha1.New(), nil
+	case AlgoSHA256:
+		return sha256.New(), nil
+	case AlgoSHA512:
+		return sha512.New(), nil
+	case AlgoCRC32:
+		return crc32.NewIEEE(), nil
+	}
+	return nil, fmt.Errorf("fsutil: unsupported hash algorithm %q", algo)
+}
+
+// HashReader streaming hash the reader contents, return hex string.
+func HashReader(r io.Reader, algo string) (string, error) {
+	h, err := NewHash(algo)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFile streaming hash the file contents, return hex string.
+//
+// Usage:
+// 	sum, err := HashFile("path/to/file.zip", fsutil.AlgoSHA256)
+func HashFile(fpath, algo string) (string, error) {
+	file, err := os.Open(fpath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return HashReader(file, algo)
+}
+
+// Md5File streaming get the md5 hex string of the file contents
+func Md5File(fpath string) (string, error) {
+	return HashFile(fpath, AlgoMD5)
+}
+
+// Sha256File streaming get the sha256 hex string of the file contents
+func Sha256File(fpath string) (string, error) {
+	return HashFile(fpath, AlgoSHA256)
+}
+
+// HashDir hash all files in the dir tree. the result is the hash of the
+// manifest contents, so it is stable for the same relative paths and contents.
+func HashDir(dirPath, algo string) (string, error) {
+	m, err := BuildManifest(NewFinder([]string{dirPath}), dirPath, algo)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err = m.WriteTo(buf); err != nil {
+		return "", err
+	}
+	return HashReader(buf, algo)
+}
+
+// ManifestEntry a file checksum line in the manifest
+type ManifestEntry struct {
+	// Sum hex string of the file hash
+	Sum string
+	// Path relative path to the manifest base dir, always use "/" as separator
+	Path string
+}
+
+// Manifest a list of file checksums, format is compatible with sha256sum.
+//
+// Format:
+// 	<hex sum>  <relative path>
+type Manifest struct {
+	// Algo hash algorithm name
+	Algo    string
+	Entries []ManifestEntry
+}
+
+// BuildManifest hash all found files of the FileFinder, paths will be
+// relative to the baseDir.
+//
+// Usage:
+// 	f := fsutil.NewFinder([]string{"dist"}).ExcludeDotFile()
+// 	m, err := fsutil.BuildManifest(f, "dist", fsutil.AlgoSHA256)
+// 	err = m.WriteFile("dist.sha256")
+func BuildManifest(f *FileFinder, baseDir, algo string) (*Manifest, error) {
+	if _, err := NewHash(algo); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{Algo: algo}
+	for _, fpath := range f.FindAll() {
+		sum, err := HashFile(fpath, algo)
+		if err != nil {
+			return nil, err
+		}
+
+		relPath, err := filepath.Rel(baseDir, fpath)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Entries = append(m.Entries, ManifestEntry{Sum: sum, Path: filepath.ToSlash(relPath)})
+	}
+
+	sort.Slice(m.Entries, func(i, j int) bool {
+		return m.Entries[i].Path < m.Entries[j].Path
+	})
+	return m, nil
+}
+
+// escape the special chars in path, same as sha256sum
+var (
+	manifestEscaper   = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+	manifestUnescaper = strings.NewReplacer("\\\\", "\\", "\\n", "\n", "\\r", "\r")
+)
+
+// ParseManifest parse manifest from reader. supports the sha256sum text
+// and binary("*" prefix path) mode line, and the escaped path line(the sum has "\" prefix).
+func ParseManifest(r io.Reader, algo string) (*Manifest, error) {
+	m := &Manifest{Algo: algo}
+	s := bufio.NewScanner(r)
+
+	var line int
+	for s.Scan() {
+		line++
+		text := strings.TrimRight(s.Text(), "\r")
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		// the line starts with "\" when the path has escaped "\\", "\n" or "\r"
+		escaped := text[0] == '\\'
+		if escaped {
+			text = text[1:]
+		}
+
+		pos := strings.IndexByte(text, ' ')
+		if pos < 1 || pos+2 > len(text) {
+			return nil, fmt.Errorf("fsutil: invalid manifest line %d: %q", line, text)
+		}
+
+		// "<sum>  <path>" or "<sum> *<path>"
+		mode := text[pos+1]
+		if mode != ' ' && mode != '*' {
+			return nil, fmt.Errorf("fsutil: invalid manifest line %d: %q", line, text)
+		}
+
+		path := text[pos+2:]
+		if escaped {
+			path = manifestUnescaper.Replace(path)
+		}
+
+		m.Entries = append(m.Entries, ManifestEntry{
+			Sum:  strings.ToLower(text[:pos]),
+			Path: path,
+		})
+	}
+
+	return m, s.Err()
+}
+
+// ReadManifestFile read and parse manifest file
+func ReadManifestFile(fpath, algo string) (*Manifest, error) {
+	file, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseManifest(file, algo)
+}
+
+// WriteTo write manifest contents to the writer
+func (m *Manifest) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, e := range m.Entries {
+		line := e.Sum + "  " + e.Path + "\n"
+		if strings.ContainsAny(e.Path, "\\\n\r") {
+			line = "\\" + e.Sum + "  " + manifestEscaper.Replace(e.Path) + "\n"
+		}
+
+		n, err := io.WriteString(w, line)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// WriteFile write manifest contents to the file
+func (m *Manifest) WriteFile(fpath string) error {
+	file, err := CreateFile(fpath, DefaultFilePerm, DefaultDirPerm)
+	if err != nil {
+		return err
+	}
+
+	if _, err = m.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// String manifest contents
+func (m *Manifest) String() string {
+	buf := new(bytes.Buffer)
+	_, _ = m.WriteTo(buf)
+	return buf.String()
+}
+
+// VerifyResult of verify dir tree by manifest
+type VerifyResult struct {
+	// Missing files are in the manifest but not exists in the dir
+	Missing []string
+	// Changed files checksum not match the manifest
+	Changed []string
+	// Extra files are exists in the dir but not in the manifest
+	Extra []string
+}
+
+// OK check there are no missing, changed and extra files.
+func (r *VerifyResult) OK() bool {
+	return len(r.Missing) == 0 && len(r.Changed) == 0 && len(r.Extra) == 0
+}
+
+// Error convert result to error, return nil on OK.
+func (r *VerifyResult) Error() error {
+	if r.OK() {
+		return nil
+	}
+
+	var ss []string
+	if len(r.Missing) > 0 {
+		ss = append(ss, "missing: "+strings.Join(r.Missing, ", "))
+	}
+	if len(r.Changed) > 0 {
+		ss = append(ss, "changed: "+strings.Join(r.Changed, ", "))
+	}
+	if len(r.Extra) > 0 {
+		ss = append(ss, "extra: "+strings.Join(r.Extra, ", "))
+	}
+	return errors.New("fsutil: verify manifest failed, " + strings.Join(ss, "; "))
+}
+
+// Verify check the files in baseDir by the manifest.
+// the finder is use for find extra files, if is nil will find all files in the baseDir.
+//
+// Usage:
+// 	m, err := fsutil.ReadManifestFile("dist.sha256", fsutil.AlgoSHA256)
+// 	ret, err := m.Verify("dist", nil)
+// 	if !ret.OK() {
+// 		fmt.Println(ret.Changed)
+// 	}
+func (m *Manifest) Verify(baseDir string, f *FileFinder) (*VerifyResult, error) {
+	ret := &VerifyResult{}
+	known := make(map[string]bool, len(m.Entries))
+
+	for _, e := range m.Entries {
+		known[e.Path] = true
+		sum, err := HashFile(filepath.Join(baseDir, filepath.FromSlash(e.Path)), m.Algo)
+		if err != nil {
+			if os.IsNotExist(err) {
+				ret.Missing = append(ret.Missing, e.Path)
+				continue
+			}
+			return nil, err
+		}
+
+		if sum != e.Sum {
+			ret.Changed = append(ret.Changed, e.Path)
+		}
+	}
+
+	if f == nil {
+		f = NewFinder([]string{baseDir})
+	}
+
+	for _, fpath := range f.FindAll() {
+		relPath, err := filepath.Rel(baseDir, fpath)
+		if err != nil {
+			return nil, err
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		if !known[relPath] {
+			ret.Extra = append(ret.Extra, relPath)
+		}
+	}
+
+	sort.Strings(ret.Extra)
+	return ret, nil
+}
+
+// VerifyManifestFile verify the files in baseDir by the manifest file.
+func VerifyManifestFile(manifestFile, baseDir, algo string) (*VerifyResult, error) {
+	m, err := ReadManifestFile(manifestFile, algo)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := m.Verify(baseDir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the manifest file self is not an extra file
+	self, err := relPathOf(baseDir, manifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	extra := ret.Extra[:0]
+	for _, relPath := range ret.Extra {
+		if relPath != self {
+			extra = append(extra, relPath)
+		}
+	}
+	ret.Extra = extra
+	return ret, nil
+}
+
+// get the slash relative path of the fpath to the baseDir
+func relPathOf(baseDir, fpath string) (string, error) {
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", err
+	}
+
+	absPath, err := filepath.Abs(fpath)
+	if err != nil {
+		return "", err
+	}
+
+	relPath, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(relPath), nil
+}
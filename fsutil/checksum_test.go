@@ -0,0 +1,109 @@
+package fsutil_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fsutil"
+)
+
+func TestHashReader(t *testing.T) {
+	tests := map[string]string{
+		fsutil.AlgoMD5:    "5d41402abc4b2a76b9719d911017c592",
+		fsutil.AlgoSHA1:   "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		fsutil.AlgoSHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		fsutil.AlgoCRC32:  "3610a686",
+	}
+
+	for algo, want := range tests {
+		sum, err := fsutil.HashReader(strings.NewReader("hello"), algo)
+		assert.NoError(t, err)
+		assert.Equal(t, want, sum, algo)
+	}
+
+	_, err := fsutil.HashReader(strings.NewReader("hello"), "not-exist")
+	assert.Error(t, err)
+
+	sum, err := fsutil.Md5File("testdata/test.jpg")
+	assert.NoError(t, err)
+	assert.Len(t, sum, 32)
+
+	_, err = fsutil.Sha256File("testdata/not-exist.jpg")
+	assert.Error(t, err)
+}
+
+func TestManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, fsutil.Mkdir(filepath.Join(dir, "sub"), 0775))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0664))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub/b.txt"), []byte("world"), 0664))
+
+	m, err := fsutil.BuildManifest(fsutil.NewFinder([]string{dir}), dir, fsutil.AlgoSHA256)
+	assert.NoError(t, err)
+	assert.Len(t, m.Entries, 2)
+	assert.Equal(t, "sub/b.txt", m.Entries[1].Path)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  a.txt\n", strings.SplitAfter(m.String(), "\n")[0])
+
+	// parse
+	pm, err := fsutil.ParseManifest(strings.NewReader(m.String()), fsutil.AlgoSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, m.Entries, pm.Entries)
+
+	_, err = fsutil.ParseManifest(bytes.NewBufferString("invalid-line"), fsutil.AlgoSHA256)
+	assert.Error(t, err)
+
+	ret, err := m.Verify(dir, nil)
+	assert.NoError(t, err)
+	assert.True(t, ret.OK())
+	assert.NoError(t, ret.Error())
+
+	dirSum, err := fsutil.HashDir(dir, fsutil.AlgoSHA256)
+	assert.NoError(t, err)
+
+	// change files
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello!"), 0664))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "sub/b.txt")))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "c.txt"), []byte("new"), 0664))
+
+	ret, err = m.Verify(dir, nil)
+	assert.NoError(t, err)
+	assert.False(t, ret.OK())
+	assert.Equal(t, []string{"a.txt"}, ret.Changed)
+	assert.Equal(t, []string{"sub/b.txt"}, ret.Missing)
+	assert.Equal(t, []string{"c.txt"}, ret.Extra)
+	assert.Contains(t, ret.Error().Error(), "missing: sub/b.txt")
+
+	newSum, err := fsutil.HashDir(dir, fsutil.AlgoSHA256)
+	assert.NoError(t, err)
+	assert.NotEqual(t, dirSum, newSum)
+
+	// manifest file
+	mFile := filepath.Join(dir, "sums.sha256")
+	assert.NoError(t, m.WriteFile(mFile))
+	ret, err = fsutil.VerifyManifestFile(mFile, dir, fsutil.AlgoSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c.txt"}, ret.Extra)
+}
+
+func TestParseManifest_escaped(t *testing.T) {
+	m := &fsutil.Manifest{Entries: []fsutil.ManifestEntry{
+		{Sum: "aa", Path: `dir\file.txt`},
+		{Sum: "bb", Path: "new\nline.txt"},
+		{Sum: "cc", Path: "normal.txt"},
+	}}
+
+	str := m.String()
+	assert.Equal(t, "\\aa  dir\\\\file.txt\n\\bb  new\\nline.txt\ncc  normal.txt\n", str)
+
+	pm, err := fsutil.ParseManifest(strings.NewReader(str), fsutil.AlgoSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, m.Entries, pm.Entries)
+}
@@ -0,0 +1,9 @@
+package fsutil
+
+// SaveMimeSignatures save the current signatures, returns the func for restore them.
+func SaveMimeSignatures() (restore func()) {
+	saved := mimeSignatures
+	return func() {
+		mimeSignatures = saved
+	}
+}
@@ -2,14 +2,15 @@ package fsutil
 
 import (
 	"archive/zip"
-	"bytes"
 	"errors"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+
+	"github.com/urionz/goutil/netutil/httpctype"
 )
 
 const (
@@ -18,7 +19,7 @@ const (
 )
 
 var (
-	// refer net/http package
+	// ImageMimeTypes the common image ext and mime types
 	ImageMimeTypes = map[string]string{
 		"bmp": "image/bmp",
 		"gif": "image/gif",
@@ -30,6 +31,10 @@ var (
 		"svg":  "image/svg+xml",
 		"ico":  "image/x-icon",
 		"webp": "image/webp",
+		"tiff": "image/tiff",
+		"heic": "image/heic",
+		"heif": "image/heif",
+		"avif": "image/avif",
 	}
 )
 
@@ -163,6 +168,7 @@ func MustCopyFile(src string, dst string) {
 }
 
 // MimeType get File Mime Type name. eg "image/png"
+// will detect by file contents, fallback to use the file ext.
 func MimeType(path string) (mime string) {
 	if path == "" {
 		return
@@ -172,8 +178,9 @@ func MimeType(path string) (mime string) {
 	if err != nil {
 		return
 	}
+	defer file.Close()
 
-	return ReaderMimeType(file)
+	return fallbackExtMime(ReaderMimeType(file), path)
 }
 
 // ReaderMimeType get the io.Reader mimeType
@@ -184,45 +191,39 @@ func MimeType(path string) (mime string) {
 // 	}
 //	mime := ReaderMimeType(file)
 func ReaderMimeType(r io.Reader) (mime string) {
-	var buf [MimeSniffLen]byte
+	var buf [MimeDetectLen]byte
 	n, _ := io.ReadFull(r, buf[:])
 	if n == 0 {
 		return ""
 	}
 
-	return http.DetectContentType(buf[:n])
+	return DetectMime(buf[:n])
 }
 
-// IsImageFile check file is image file.
-func IsImageFile(path string) bool {
-	mime := MimeType(path)
-	if mime == "" {
-		return false
+// FileMimeSignature detect the file magic number signature. return nil on not matched.
+func FileMimeSignature(path string) *MimeSignature {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
 	}
+	defer file.Close()
 
-	for _, imgMime := range ImageMimeTypes {
-		if imgMime == mime {
-			return true
-		}
-	}
-	return false
+	var buf [MimeDetectLen]byte
+	n, _ := io.ReadFull(file, buf[:])
+	return DetectMimeSignature(buf[:n])
 }
 
-// IsZipFile check is zip file.
-// from https://blog.csdn.net/wangshubo1989/article/details/71743374
-func IsZipFile(filepath string) bool {
-	f, err := os.Open(filepath)
-	if err != nil {
-		return false
-	}
-	defer f.Close()
-
-	buf := make([]byte, 4)
-	if n, err := f.Read(buf); err != nil || n < 4 {
-		return false
-	}
+// IsImageFile check file is image file. detect by the file contents.
+func IsImageFile(path string) bool {
+	sig := FileMimeSignature(path)
+	return sig != nil && strings.HasPrefix(sig.Mime, "image/")
+}
 
-	return bytes.Equal(buf, []byte("PK\x03\x04"))
+// IsZipFile check is zip file. detect by the file contents,
+// zip based file also is zip file. eg: docx, jar
+func IsZipFile(filepath string) bool {
+	sig := FileMimeSignature(filepath)
+	return sig != nil && sig.Is(httpctype.MIMEZIP)
 }
 
 // Unzip a zip archive
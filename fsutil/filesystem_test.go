@@ -57,7 +57,9 @@ func TestMkdir(t *testing.T) {
 		assert.NoError(t, fsutil.Mkdir("./testdata/sub/sub22", 0666))
 		assert.NoError(t, fsutil.Mkdir("./testdata/sub/sub23/sub31", 0777)) // 066X will error
 
-		assert.NoError(t, os.RemoveAll("./testdata/sub"))
+		assert.NoError(t, os.RemoveAll("./testdata/sub/sub21"))
+		assert.NoError(t, os.RemoveAll("./testdata/sub/sub22"))
+		assert.NoError(t, os.RemoveAll("./testdata/sub/sub23"))
 	}
 }
 
@@ -77,14 +79,14 @@ func TestCreateFile(t *testing.T) {
 	file, err = fsutil.CreateFile("./testdata/sub/test.txt", 0664, 0777)
 	if assert.NoError(t, err) {
 		assert.Equal(t, "./testdata/sub/test.txt", file.Name())
-		assert.NoError(t, os.RemoveAll("./testdata/sub"))
+		assert.NoError(t, os.Remove(file.Name()))
 		assert.NoError(t, file.Close())
 	}
 
 	file, err = fsutil.CreateFile("./testdata/sub/sub2/test.txt", 0664, 0777)
 	if assert.NoError(t, err) {
 		assert.Equal(t, "./testdata/sub/sub2/test.txt", file.Name())
-		assert.NoError(t, os.RemoveAll("./testdata/sub"))
+		assert.NoError(t, os.Remove(file.Name()))
 		assert.NoError(t, file.Close())
 	}
 }
@@ -0,0 +1,414 @@
+package fsutil
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/urionz/goutil/netutil/httpctype"
+)
+
+const (
+	// MimeDetectLen read length for detect file mime type by magic number.
+	// some container formats(eg: docx, epub) need more bytes than MimeSniffLen.
+	MimeDetectLen = 3072
+)
+
+// MimeSignature a magic number signature for detect file mime type.
+type MimeSignature struct {
+	// Mime type name. eg: "image/png"
+	Mime string
+	// Ext the common file ext of the mime. eg: ".png"
+	Ext string
+	// Offset of the Magic bytes in the file header
+	Offset int
+	// Magic bytes at the Offset
+	Magic []byte
+	// Match custom matcher func. if not nil, will use it instead of Offset+Magic
+	Match func(buf []byte) bool
+	// Children more specific signatures, check them after current is matched.
+	// eg: zip -> docx, xlsx, epub
+	Children []*MimeSignature
+
+	parent *MimeSignature
+}
+
+// Matched check the signature is matched the buf
+func (s *MimeSignature) Matched(buf []byte) bool {
+	if s.Match != nil {
+		return s.Match(buf)
+	}
+
+	end := s.Offset + len(s.Magic)
+	if len(s.Magic) == 0 || len(buf) < end {
+		return false
+	}
+	return bytes.Equal(buf[s.Offset:end], s.Magic)
+}
+
+// Is check the signature or its parents mime is equals the mime.
+//
+// Usage:
+// 	sig := DetectMimeSignature(buf) // docx signature
+// 	sig.Is(httpctype.MIMEZIP) // true
+func (s *MimeSignature) Is(mime string) bool {
+	for sig := s; sig != nil; sig = sig.parent {
+		if sig.Mime == mime {
+			return true
+		}
+	}
+	return false
+}
+
+// Parent get the parent signature. eg: zip for docx
+func (s *MimeSignature) Parent() *MimeSignature {
+	return s.parent
+}
+
+func (s *MimeSignature) detect(buf []byte) *MimeSignature {
+	if !s.Matched(buf) {
+		return nil
+	}
+
+	for _, child := range s.Children {
+		if sig := child.detect(buf); sig != nil {
+			return sig
+		}
+	}
+	return s
+}
+
+func linkParents(sigs []*MimeSignature, parent *MimeSignature) {
+	for _, sig := range sigs {
+		sig.parent = parent
+		linkParents(sig.Children, sig)
+	}
+}
+
+func prefixSig(mime, ext string, magic string) *MimeSignature {
+	return &MimeSignature{Mime: mime, Ext: ext, Magic: []byte(magic)}
+}
+
+func offsetSig(mime, ext string, offset int, magic string) *MimeSignature {
+	return &MimeSignature{Mime: mime, Ext: ext, Offset: offset, Magic: []byte(magic)}
+}
+
+func matchSig(mime, ext string, fn func(buf []byte) bool) *MimeSignature {
+	return &MimeSignature{Mime: mime, Ext: ext, Match: fn}
+}
+
+// ftyp box based formats. see ISO/IEC 14496-12
+func ftypMatcher(brands ...string) func(buf []byte) bool {
+	return func(buf []byte) bool {
+		if len(buf) < 12 || string(buf[4:8]) != "ftyp" {
+			return false
+		}
+
+		major := string(buf[8:12])
+		for _, brand := range brands {
+			if major == brand {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// zipContains check there is a local file entry name has the prefix.
+func zipContains(prefix string) func(buf []byte) bool {
+	header := []byte("PK\x03\x04")
+	return func(buf []byte) bool {
+		for pos := bytes.Index(buf, header); pos >= 0; {
+			hdr := buf[pos:]
+			if len(hdr) < 30 {
+				return false
+			}
+
+			nameLen := int(hdr[26]) | int(hdr[27])<<8
+			if 30+nameLen <= len(hdr) && strings.HasPrefix(string(hdr[30:30+nameLen]), prefix) {
+				return true
+			}
+
+			next := bytes.Index(hdr[len(header):], header)
+			if next < 0 {
+				return false
+			}
+			pos += len(header) + next
+		}
+		return false
+	}
+}
+
+// zipMimetype check the first stored file "mimetype" contents. used by epub, odt
+func zipMimetype(mime string) func(buf []byte) bool {
+	want := "mimetype" + mime
+	return func(buf []byte) bool {
+		return len(buf) >= 30+len(want) && string(buf[30:30+len(want)]) == want
+	}
+}
+
+func isSVG(buf []byte) bool {
+	buf = bytes.TrimLeft(buf, "\xef\xbb\xbf \t\r\n")
+	if len(buf) == 0 || buf[0] != '<' {
+		return false
+	}
+
+	if bytes.HasPrefix(buf, []byte("<svg")) {
+		return true
+	}
+	return (bytes.HasPrefix(buf, []byte("<?xml")) || bytes.HasPrefix(buf, []byte("<!DOCTYPE svg"))) &&
+		bytes.Contains(buf, []byte("<svg"))
+}
+
+// the builtin magic number signatures
+var mimeSignatures = []*MimeSignature{
+	// image
+	prefixSig(httpctype.MIMEPNG, ".png", "\x89PNG\r\n\x1a\n"),
+	prefixSig(httpctype.MIMEJPEG, ".jpg", "\xff\xd8\xff"),
+	prefixSig(httpctype.MIMEGIF, ".gif", "GIF87a"),
+	prefixSig(httpctype.MIMEGIF, ".gif", "GIF89a"),
+	matchSig(httpctype.MIMEWEBP, ".webp", func(buf []byte) bool {
+		return len(buf) >= 12 && string(buf[:4]) == "RIFF" && string(buf[8:12]) == "WEBP"
+	}),
+	matchSig(httpctype.MIMEHEIC, ".heic", ftypMatcher("heic", "heix", "hevc", "hevx", "heim", "heis")),
+	matchSig(httpctype.MIMEHEIF, ".heif", ftypMatcher("mif1", "msf1")),
+	matchSig(httpctype.MIMEAVIF, ".avif", ftypMatcher("avif", "avis")),
+	prefixSig(httpctype.MIMEBMP, ".bmp", "BM"),
+	prefixSig(httpctype.MIMEICO, ".ico", "\x00\x00\x01\x00"),
+	prefixSig(httpctype.MIMETIFF, ".tiff", "II*\x00"),
+	prefixSig(httpctype.MIMETIFF, ".tiff", "MM\x00*"),
+	prefixSig(httpctype.MIMEPSD, ".psd", "8BPS"),
+	matchSig(httpctype.MIMESVG, ".svg", isSVG),
+	// audio and video
+	matchSig(httpctype.MIMEM4A, ".m4a", ftypMatcher("M4A ", "M4B ")),
+	matchSig(httpctype.MIMEMOV, ".mov", ftypMatcher("qt  ")),
+	matchSig(httpctype.MIMEMP4, ".mp4", ftypMatcher("isom", "iso2", "iso5", "mp41", "mp42", "avc1", "dash", "M4V ", "MSNV")),
+	prefixSig(httpctype.MIMEMP3, ".mp3", "ID3"),
+	prefixSig(httpctype.MIMEMP3, ".mp3", "\xff\xfb"),
+	prefixSig(httpctype.MIMEFLAC, ".flac", "fLaC"),
+	prefixSig(httpctype.MIMEOGG, ".ogg", "OggS"),
+	prefixSig(httpctype.MIMEMIDI, ".mid", "MThd"),
+	matchSig(httpctype.MIMEWAV, ".wav", func(buf []byte) bool {
+		return len(buf) >= 12 && string(buf[:4]) == "RIFF" && string(buf[8:12]) == "WAVE"
+	}),
+	matchSig(httpctype.MIMEAVI, ".avi", func(buf []byte) bool {
+		return len(buf) >= 12 && string(buf[:4]) == "RIFF" && string(buf[8:12]) == "AVI "
+	}),
+	{
+		Mime:  httpctype.MIMEMKV,
+		Ext:   ".mkv",
+		Magic: []byte("\x1a\x45\xdf\xa3"),
+		Children: []*MimeSignature{
+			matchSig(httpctype.MIMEWEBM, ".webm", func(buf []byte) bool {
+				return bytes.Contains(buf[:minInt(len(buf), 64)], []byte("webm"))
+			}),
+		},
+	},
+	prefixSig(httpctype.MIMEFLV, ".flv", "FLV\x01"),
+	// font
+	prefixSig(httpctype.MIMEWOFF, ".woff", "wOFF"),
+	prefixSig(httpctype.MIMEWOFF2, ".woff2", "wOF2"),
+	prefixSig(httpctype.MIMETTF, ".ttf", "\x00\x01\x00\x00\x00"),
+	prefixSig(httpctype.MIMEOTF, ".otf", "OTTO"),
+	// document and archive
+	prefixSig(httpctype.MIMEPDF, ".pdf", "%PDF-"),
+	prefixSig(httpctype.MIMERTF, ".rtf", "{\\rtf"),
+	{
+		Mime:  httpctype.MIMEZIP,
+		Ext:   ".zip",
+		Magic: []byte("PK\x03\x04"),
+		Children: []*MimeSignature{
+			matchSig(httpctype.MIMEEPUB, ".epub", zipMimetype(httpctype.MIMEEPUB)),
+			matchSig(httpctype.MIMEODT, ".odt", zipMimetype(httpctype.MIMEODT)),
+			matchSig(httpctype.MIMEODS, ".ods", zipMimetype(httpctype.MIMEODS)),
+			matchSig(httpctype.MIMEODP, ".odp", zipMimetype(httpctype.MIMEODP)),
+			matchSig(httpctype.MIMEDOCX, ".docx", zipContains("word/")),
+			matchSig(httpctype.MIMEXLSX, ".xlsx", zipContains("xl/")),
+			matchSig(httpctype.MIMEPPTX, ".pptx", zipContains("ppt/")),
+			matchSig(httpctype.MIMEAPK, ".apk", zipContains("AndroidManifest.xml")),
+			matchSig(httpctype.MIMEJAR, ".jar", zipContains("META-INF/MANIFEST.MF")),
+		},
+	},
+	prefixSig(httpctype.MIMEZIP, ".zip", "PK\x05\x06"), // empty archive
+	prefixSig(httpctype.MIMEGZIP, ".gz", "\x1f\x8b"),
+	prefixSig(httpctype.MIMEBZIP2, ".bz2", "BZh"),
+	prefixSig(httpctype.MIMEXZ, ".xz", "\xfd7zXZ\x00"),
+	prefixSig(httpctype.MIMEZSTD, ".zst", "\x28\xb5\x2f\xfd"),
+	prefixSig(httpctype.MIME7Z, ".7z", "7z\xbc\xaf\x27\x1c"),
+	prefixSig(httpctype.MIMERAR, ".rar", "Rar!\x1a\x07"),
+	offsetSig(httpctype.MIMETAR, ".tar", 257, "ustar"),
+	prefixSig(httpctype.MIMEOLE, ".msi", "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"),
+	// database and binary
+	prefixSig(httpctype.MIMESQLite, ".sqlite", "SQLite format 3\x00"),
+	prefixSig(httpctype.MIMEParquet, ".parquet", "PAR1"),
+	prefixSig(httpctype.MIMEWASM, ".wasm", "\x00asm"),
+	prefixSig(httpctype.MIMEELF, "", "\x7fELF"),
+	prefixSig(httpctype.MIMEEXE, ".exe", "MZ"),
+	prefixSig(httpctype.MIMEClass, ".class", "\xca\xfe\xba\xbe"),
+}
+
+func init() {
+	linkParents(mimeSignatures, nil)
+}
+
+// ExtMimeTypes mapping file ext to mime type, use for fallback when
+// can not detect the mime type by file contents.
+var ExtMimeTypes = map[string]string{
+	".txt":  httpctype.MIMEText,
+	".log":  httpctype.MIMEText,
+	".htm":  httpctype.MIMEHTML,
+	".html": httpctype.MIMEHTML,
+	".css":  httpctype.MIMECSS,
+	".csv":  httpctype.MIMECSV,
+	".tsv":  httpctype.MIMETSV,
+	".md":   httpctype.MIMEMarkdown,
+	".js":   httpctype.MIMEJS,
+	".mjs":  httpctype.MIMEJS,
+	".json": httpctype.MIMEJSON,
+	".xml":  httpctype.MIMEXML,
+	".yml":  httpctype.MIMEYAML,
+	".yaml": httpctype.MIMEYAML,
+	".toml": httpctype.MIMETOML,
+	".wasm": httpctype.MIMEWASM,
+	".pb":   httpctype.MIMEPROTOBUF,
+
+	".pdf":  httpctype.MIMEPDF,
+	".zip":  httpctype.MIMEZIP,
+	".gz":   httpctype.MIMEGZIP,
+	".tar":  httpctype.MIMETAR,
+	".7z":   httpctype.MIME7Z,
+	".rar":  httpctype.MIMERAR,
+	".doc":  httpctype.MIMEDOC,
+	".xls":  httpctype.MIMEXLS,
+	".ppt":  httpctype.MIMEPPT,
+	".docx": httpctype.MIMEDOCX,
+	".xlsx": httpctype.MIMEXLSX,
+	".pptx": httpctype.MIMEPPTX,
+	".epub": httpctype.MIMEEPUB,
+
+	".png":  httpctype.MIMEPNG,
+	".jpg":  httpctype.MIMEJPEG,
+	".jpeg": httpctype.MIMEJPEG,
+	".gif":  httpctype.MIMEGIF,
+	".bmp":  httpctype.MIMEBMP,
+	".webp": httpctype.MIMEWEBP,
+	".svg":  httpctype.MIMESVG,
+	".ico":  httpctype.MIMEICO,
+	".heic": httpctype.MIMEHEIC,
+	".avif": httpctype.MIMEAVIF,
+
+	".mp3":  httpctype.MIMEMP3,
+	".wav":  httpctype.MIMEWAV,
+	".mp4":  httpctype.MIMEMP4,
+	".webm": httpctype.MIMEWEBM,
+
+	".sqlite":  httpctype.MIMESQLite,
+	".db":      httpctype.MIMESQLite,
+	".parquet": httpctype.MIMEParquet,
+}
+
+// AddMimeSignature add custom magic number signatures.
+// the custom signatures will be checked before the builtin signatures.
+//
+// NOTICE: it is not concurrent safe, please call it on init.
+//
+// Usage:
+// 	fsutil.AddMimeSignature(&fsutil.MimeSignature{
+// 		Mime:  "application/x-my-format",
+// 		Ext:   ".myf",
+// 		Magic: []byte("MYF1"),
+// 	})
+func AddMimeSignature(sigs ...*MimeSignature) {
+	linkParents(sigs, nil)
+	mimeSignatures = append(sigs, mimeSignatures...)
+}
+
+// DetectMimeSignature detect the matched signature by file header bytes.
+// will return nil on not matched.
+func DetectMimeSignature(buf []byte) *MimeSignature {
+	for _, sig := range mimeSignatures {
+		if matched := sig.detect(buf); matched != nil {
+			return matched
+		}
+	}
+	return nil
+}
+
+// DetectMime detect mime type by file header bytes.
+// will fallback to use http.DetectContentType() on no signature matched.
+func DetectMime(buf []byte) string {
+	if len(buf) == 0 {
+		return ""
+	}
+
+	if sig := DetectMimeSignature(buf); sig != nil {
+		return sig.Mime
+	}
+
+	if len(buf) > MimeSniffLen {
+		buf = buf[:MimeSniffLen]
+	}
+	return http.DetectContentType(buf)
+}
+
+// ExtMimeType get mime type by file ext, the ext can be with or without dot.
+// will fallback to use mime.TypeByExtension().
+//
+// Usage:
+// 	ExtMimeType(".json") // "application/json"
+// 	ExtMimeType("png") // "image/png"
+func ExtMimeType(ext string) string {
+	if ext == "" {
+		return ""
+	}
+
+	if ext[0] != '.' {
+		ext = "." + ext
+	}
+
+	ext = strings.ToLower(ext)
+	if mt, ok := ExtMimeTypes[ext]; ok {
+		return mt
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// fallback to ext mime type on detected result is not exactly.
+// the plain text only can be refined to the textual types. eg: ".json", ".md"
+func fallbackExtMime(detected, fpath string) string {
+	isText := strings.HasPrefix(detected, httpctype.MIMEText)
+	if detected != httpctype.MIMEBinary && !isText {
+		return detected
+	}
+
+	mt := ExtMimeType(filepath.Ext(fpath))
+	if mt == "" || isText && !isTextualMime(mt) {
+		return detected
+	}
+	return mt
+}
+
+// the mime types which the contents are plain text
+var textualMimes = map[string]bool{
+	httpctype.MIMEJSON: true,
+	httpctype.MIMEXML:  true,
+	httpctype.MIMEYAML: true,
+	httpctype.MIMETOML: true,
+	httpctype.MIMEJS:   true,
+}
+
+func isTextualMime(mt string) bool {
+	if pos := strings.IndexByte(mt, ';'); pos > 0 {
+		mt = mt[:pos]
+	}
+	return strings.HasPrefix(mt, "text/") || textualMimes[mt]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
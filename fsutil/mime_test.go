@@ -0,0 +1,112 @@
+package fsutil_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fsutil"
+	"github.com/urionz/goutil/netutil/httpctype"
+)
+
+func makeZip(t *testing.T, names ...string) []byte {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte("data"))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestDetectMime(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"\x89PNG\r\n\x1a\n0000", httpctype.MIMEPNG},
+		{"RIFF\x00\x00\x00\x00WEBPVP8 ", httpctype.MIMEWEBP},
+		{"\x00\x00\x00\x18ftypheic\x00\x00\x00\x00", httpctype.MIMEHEIC},
+		{"\x00\x00\x00\x18ftypavif\x00\x00\x00\x00", httpctype.MIMEAVIF},
+		{"\x00\x00\x00\x18ftypisom\x00\x00\x00\x00", httpctype.MIMEMP4},
+		{"PAR1\x15\x04", httpctype.MIMEParquet},
+		{"SQLite format 3\x00\x10\x00", httpctype.MIMESQLite},
+		{"\x00asm\x01\x00\x00\x00", httpctype.MIMEWASM},
+		{"%PDF-1.7", httpctype.MIMEPDF},
+		{`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`, httpctype.MIMESVG},
+		{"text", "text/plain; charset=utf-8"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fsutil.DetectMime([]byte(tt.data)), "data: %q", tt.data)
+	}
+
+	assert.Equal(t, httpctype.MIMEZIP, fsutil.DetectMime(makeZip(t, "a.txt")))
+	assert.Equal(t, httpctype.MIMEDOCX, fsutil.DetectMime(makeZip(t, "[Content_Types].xml", "_rels/.rels", "word/document.xml")))
+	assert.Equal(t, httpctype.MIMEXLSX, fsutil.DetectMime(makeZip(t, "[Content_Types].xml", "xl/workbook.xml")))
+	assert.Equal(t, httpctype.MIMEJAR, fsutil.DetectMime(makeZip(t, "META-INF/MANIFEST.MF")))
+
+	sig := fsutil.DetectMimeSignature(makeZip(t, "word/document.xml"))
+	if assert.NotNil(t, sig) {
+		assert.Equal(t, ".docx", sig.Ext)
+		assert.True(t, sig.Is(httpctype.MIMEZIP))
+		assert.Equal(t, httpctype.MIMEZIP, sig.Parent().Mime)
+	}
+	assert.Nil(t, fsutil.DetectMimeSignature([]byte("text")))
+}
+
+func TestAddMimeSignature(t *testing.T) {
+	t.Cleanup(fsutil.SaveMimeSignatures())
+
+	fsutil.AddMimeSignature(&fsutil.MimeSignature{
+		Mime:   "application/x-goutil-test",
+		Ext:    ".gut",
+		Offset: 2,
+		Magic:  []byte("GUT"),
+	})
+
+	assert.Equal(t, "application/x-goutil-test", fsutil.DetectMime([]byte("00GUT01")))
+	assert.Equal(t, "text/plain; charset=utf-8", fsutil.DetectMime([]byte("0GUT01")))
+}
+
+func TestExtMimeType(t *testing.T) {
+	assert.Equal(t, "", fsutil.ExtMimeType(""))
+	assert.Equal(t, httpctype.MIMEJSON, fsutil.ExtMimeType(".json"))
+	assert.Equal(t, httpctype.MIMEPNG, fsutil.ExtMimeType("PNG"))
+	assert.Equal(t, httpctype.MIMEMarkdown, fsutil.ExtMimeType("md"))
+}
+
+func TestMimeType_fallbackExt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mime")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonFile := filepath.Join(dir, "some.json")
+	assert.NoError(t, ioutil.WriteFile(jsonFile, []byte(`{"name": "inhere"}`), 0664))
+	assert.Equal(t, httpctype.MIMEJSON, fsutil.MimeType(jsonFile))
+
+	docxFile := filepath.Join(dir, "some.docx")
+	assert.NoError(t, ioutil.WriteFile(docxFile, makeZip(t, "word/document.xml"), 0664))
+	assert.Equal(t, httpctype.MIMEDOCX, fsutil.MimeType(docxFile))
+	assert.True(t, fsutil.IsZipFile(docxFile))
+	assert.False(t, fsutil.IsImageFile(docxFile))
+
+	// the plain text can not be overridden by the non textual ext.
+	fakePng := filepath.Join(dir, "fake.png")
+	assert.NoError(t, ioutil.WriteFile(fakePng, []byte("not image"), 0664))
+	assert.False(t, fsutil.IsImageFile(fakePng))
+	assert.Equal(t, "text/plain; charset=utf-8", fsutil.MimeType(fakePng))
+
+	// unknown binary contents use the ext
+	binPng := filepath.Join(dir, "bin.png")
+	assert.NoError(t, ioutil.WriteFile(binPng, []byte{0x00, 0x01, 0x02}, 0664))
+	assert.Equal(t, httpctype.MIMEPNG, fsutil.MimeType(binPng))
+}
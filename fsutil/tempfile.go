@@ -0,0 +1,273 @@
+package fsutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TB the minimal interface of the testing.TB, use for auto cleanup temp files.
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Fatalf(format string, args ...interface{})
+}
+
+// CloseFunc for close and remove the temp file or dir.
+type CloseFunc func() error
+
+// TempFile create a temp file, returns the file and closer func for close and remove it.
+// the pattern is same of the ioutil.TempFile(). eg: "app-*.log"
+//
+// Usage:
+// 	file, closeFn, err := fsutil.TempFile("", "app-*.log")
+// 	if err != nil {
+// 		return err
+// 	}
+// 	defer closeFn()
+func TempFile(dir, pattern string) (*os.File, CloseFunc, error) {
+	file, err := ioutil.TempFile(dir, pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, func() error {
+		_ = file.Close()
+		return os.Remove(file.Name())
+	}, nil
+}
+
+// TempFileWith create a temp file with contents, returns the file path and closer func.
+func TempFileWith(dir, pattern, contents string) (string, CloseFunc, error) {
+	file, closeFn, err := TempFile(dir, pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, err = file.WriteString(contents); err == nil {
+		err = file.Close()
+	}
+
+	if err != nil {
+		_ = closeFn()
+		return "", nil, err
+	}
+	return file.Name(), closeFn, nil
+}
+
+// TempDir create a temp dir, returns the dir path and closer func for remove it.
+// the pattern is same of the ioutil.TempDir(). eg: "build-*"
+func TempDir(dir, pattern string) (string, CloseFunc, error) {
+	dirPath, err := ioutil.TempDir(dir, pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return dirPath, func() error {
+		return os.RemoveAll(dirPath)
+	}, nil
+}
+
+// TempTree create a temp dir and seeding files from map[path]contents.
+// see WriteTree() for more.
+func TempTree(pattern string, files map[string]string) (string, CloseFunc, error) {
+	dirPath, closeFn, err := TempDir("", pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = WriteTree(dirPath, files); err != nil {
+		_ = closeFn()
+		return "", nil, err
+	}
+	return dirPath, closeFn, nil
+}
+
+// TempFileT create a temp file, it will be auto removed by tb.Cleanup()
+func TempFileT(tb TB, pattern string) *os.File {
+	tb.Helper()
+
+	file, closeFn, err := TempFile("", pattern)
+	if err != nil {
+		tb.Fatalf("fsutil: create temp file error: %v", err)
+		return nil
+	}
+
+	tb.Cleanup(func() { _ = closeFn() })
+	return file
+}
+
+// TempDirT create a temp dir, it will be auto removed by tb.Cleanup()
+func TempDirT(tb TB, pattern string) string {
+	tb.Helper()
+
+	dirPath, closeFn, err := TempDir("", pattern)
+	if err != nil {
+		tb.Fatalf("fsutil: create temp dir error: %v", err)
+		return ""
+	}
+
+	tb.Cleanup(func() { _ = closeFn() })
+	return dirPath
+}
+
+// TempTreeT create a temp dir with files, it will be auto removed by tb.Cleanup()
+//
+// Usage:
+// 	dir := fsutil.TempTreeT(t, map[string]string{
+// 		"config/app.json": `{"name": "app"}`,
+// 		"logs/": "", // empty dir
+// 	})
+func TempTreeT(tb TB, files map[string]string) string {
+	tb.Helper()
+
+	dirPath := TempDirT(tb, "tree-*")
+	if err := WriteTree(dirPath, files); err != nil {
+		tb.Fatalf("fsutil: write temp tree error: %v", err)
+	}
+	return dirPath
+}
+
+// WriteTree write files from map[path]contents to the baseDir.
+// the path is relative to the baseDir, use "/" as separator.
+// if path ends with "/", will create an empty dir.
+func WriteTree(baseDir string, files map[string]string) error {
+	tree := NewFixtureTree()
+	for fpath, contents := range files {
+		if strings.HasSuffix(fpath, "/") {
+			tree.Dir(fpath)
+		} else {
+			tree.File(fpath, contents)
+		}
+	}
+	return tree.BuildIn(baseDir)
+}
+
+// fixture tree node
+type fixtureNode struct {
+	path  string
+	isDir bool
+	data  []byte
+	perm  os.FileMode
+}
+
+// FixtureTree builder, for quick build a dir tree with files.
+//
+// Usage:
+// 	dir := fsutil.NewFixtureTree().
+// 		File("go.mod", "module demo").
+// 		FileMode("bin/run.sh", "#!/bin/sh", 0755).
+// 		Dir("tmp").
+// 		BuildT(t)
+type FixtureTree struct {
+	nodes map[string]*fixtureNode
+	// the first invalid path error, will be returned on build
+	err error
+}
+
+// NewFixtureTree create
+func NewFixtureTree() *FixtureTree {
+	return &FixtureTree{nodes: make(map[string]*fixtureNode)}
+}
+
+// File add a file with contents
+func (ft *FixtureTree) File(fpath, contents string) *FixtureTree {
+	return ft.FileMode(fpath, contents, DefaultFilePerm)
+}
+
+// FileMode add a file with contents and file perm
+func (ft *FixtureTree) FileMode(fpath, contents string, perm os.FileMode) *FixtureTree {
+	return ft.Bytes(fpath, []byte(contents), perm)
+}
+
+// Bytes add a file with binary contents and file perm
+func (ft *FixtureTree) Bytes(fpath string, data []byte, perm os.FileMode) *FixtureTree {
+	return ft.add(fpath, &fixtureNode{data: data, perm: perm})
+}
+
+// Dir add an empty dir
+func (ft *FixtureTree) Dir(dirPath string) *FixtureTree {
+	return ft.add(dirPath, &fixtureNode{isDir: true, perm: DefaultDirPerm})
+}
+
+// add the node by the cleaned path, the path must be in the base dir. eg: "../x" is invalid
+func (ft *FixtureTree) add(fpath string, node *fixtureNode) *FixtureTree {
+	cleaned := path.Clean(strings.Trim(fpath, "/"))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") || filepath.VolumeName(fpath) != "" {
+		if ft.err == nil {
+			ft.err = fmt.Errorf("fsutil: invalid fixture path %q, must be in the base dir", fpath)
+		}
+		return ft
+	}
+
+	node.path = cleaned
+	ft.nodes[cleaned] = node
+	return ft
+}
+
+// Paths get all added paths, sorted.
+func (ft *FixtureTree) Paths() []string {
+	paths := make([]string, 0, len(ft.nodes))
+	for fpath := range ft.nodes {
+		paths = append(paths, fpath)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// BuildIn build the tree in the baseDir
+func (ft *FixtureTree) BuildIn(baseDir string) error {
+	if ft.err != nil {
+		return ft.err
+	}
+
+	for _, fpath := range ft.Paths() {
+		node := ft.nodes[fpath]
+		fullPath := filepath.Join(baseDir, filepath.FromSlash(node.path))
+
+		if node.isDir {
+			if err := os.MkdirAll(fullPath, node.perm); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), DefaultDirPerm); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(fullPath, node.data, node.perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Build the tree in a new temp dir. returns the dir path and closer func.
+func (ft *FixtureTree) Build(pattern string) (string, CloseFunc, error) {
+	dirPath, closeFn, err := TempDir("", pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = ft.BuildIn(dirPath); err != nil {
+		_ = closeFn()
+		return "", nil, err
+	}
+	return dirPath, closeFn, nil
+}
+
+// BuildT build the tree in a new temp dir, it will be auto removed by tb.Cleanup()
+func (ft *FixtureTree) BuildT(tb TB) string {
+	tb.Helper()
+
+	dirPath := TempDirT(tb, "fixture-*")
+	if err := ft.BuildIn(dirPath); err != nil {
+		tb.Fatalf("fsutil: build fixture tree error: %v", err)
+	}
+	return dirPath
+}
@@ -0,0 +1,95 @@
+package fsutil_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fsutil"
+)
+
+func TestTempFile(t *testing.T) {
+	file, closeFn, err := fsutil.TempFile("", "app-*.log")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(file.Name(), ".log"))
+	assert.True(t, fsutil.IsFile(file.Name()))
+	assert.NoError(t, closeFn())
+	assert.False(t, fsutil.PathExists(file.Name()))
+
+	fpath, closeFn, err := fsutil.TempFileWith("", "data-*.txt", "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(fsutil.MustReadFile(fpath)))
+	assert.NoError(t, closeFn())
+	assert.False(t, fsutil.PathExists(fpath))
+
+	dir, closeFn, err := fsutil.TempDir("", "build-*")
+	assert.NoError(t, err)
+	assert.True(t, fsutil.IsDir(dir))
+	assert.NoError(t, closeFn())
+	assert.False(t, fsutil.PathExists(dir))
+}
+
+func TestTempTree(t *testing.T) {
+	dir, closeFn, err := fsutil.TempTree("tree-*", map[string]string{
+		"a.txt":     "a",
+		"sub/b.txt": "b",
+		"empty/":    "",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", string(fsutil.MustReadFile(filepath.Join(dir, "sub", "b.txt"))))
+	assert.True(t, fsutil.IsDir(filepath.Join(dir, "empty")))
+	assert.NoError(t, closeFn())
+	assert.False(t, fsutil.PathExists(dir))
+}
+
+func TestTempDirT(t *testing.T) {
+	var dir, fpath string
+	t.Run("cleanup", func(t *testing.T) {
+		dir = fsutil.TempDirT(t, "sub-*")
+		file := fsutil.TempFileT(t, "*.txt")
+		fpath = file.Name()
+
+		assert.True(t, fsutil.IsDir(dir))
+		assert.True(t, fsutil.IsFile(fpath))
+	})
+
+	assert.False(t, fsutil.PathExists(dir))
+	assert.False(t, fsutil.PathExists(fpath))
+}
+
+func TestFixtureTree(t *testing.T) {
+	tree := fsutil.NewFixtureTree().
+		File("/go.mod", "module demo").
+		FileMode("bin/run.sh", "#!/bin/sh", 0755).
+		Dir("tmp/")
+	assert.Equal(t, []string{"bin/run.sh", "go.mod", "tmp"}, tree.Paths())
+
+	dir := tree.BuildT(t)
+	assert.True(t, fsutil.IsDir(filepath.Join(dir, "tmp")))
+
+	bs, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, "module demo", string(bs))
+
+	fi, err := os.Stat(filepath.Join(dir, "bin/run.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), fi.Mode().Perm())
+
+	// the path is cleaned
+	tree = fsutil.NewFixtureTree().File("a/./b/../c.txt", "c")
+	assert.Equal(t, []string{"a/c.txt"}, tree.Paths())
+
+	// the path out of the base dir
+	for _, invalid := range []string{"../x", "/../x", "a/../../x", "a/.."} {
+		tree = fsutil.NewFixtureTree().File("ok.txt", "ok").File(invalid, "x")
+		dir, closeFn, err := tree.Build("fixture-*")
+		assert.Error(t, err, invalid)
+		assert.Empty(t, dir)
+		assert.Nil(t, closeFn)
+	}
+	_, _, err = fsutil.NewFixtureTree().Dir("../tmp").Build("fixture-*")
+	assert.Error(t, err)
+}
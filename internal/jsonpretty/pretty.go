@@ -0,0 +1,253 @@
+// Package jsonpretty is the JSON pretty printer shared by the jsonutil and the fmtutil.
+//
+// the fmtutil can not import the jsonutil, the jsonutil imports fmtutil by goutil and mathutil.
+package jsonpretty
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/json-iterator/go"
+	"github.com/urionz/color"
+)
+
+var parser = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Options for format the JSON. see jsonutil.PrettyOptions
+type Options struct {
+	// Indent string for each level
+	Indent string
+	// SortKeys sort the object keys. default keep the original order
+	SortKeys bool
+	// MaxWidth if > 0, the array without object will be printed in one line
+	// when it fits the width. eg: "tags": [1, 2, 3]
+	MaxWidth int
+	// Color render the tokens with ANSI colors by the Theme
+	Color bool
+	// Theme the colors for the tokens, must not be nil
+	Theme *Theme
+}
+
+// Theme the ANSI color codes for the JSON tokens. eg: color.FgGreen.Code()
+type Theme struct {
+	Key, String, Number, Bool, Null string
+}
+
+// Marshal encode the value and format it. see Format()
+func Marshal(v interface{}, opts *Options) ([]byte, error) {
+	bs, err := parser.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Format(bs, opts)
+}
+
+// Format the JSON bytes, the data after the top-level value will return error.
+func Format(json []byte, opts *Options) ([]byte, error) {
+	iter := parser.BorrowIterator(json)
+	defer parser.ReturnIterator(iter)
+
+	node := readPrettyNode(iter)
+	if iter.Error == nil {
+		// skip the whitespaces, the io.EOF error will be set on there is no more data
+		iter.WhatIsNext()
+		if iter.Error == nil {
+			iter.ReportError("Format", "unexpected data after the top-level value")
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+
+	p := &prettyPrinter{opts: opts}
+	p.write(node, 0, 0)
+	return p.buf.Bytes(), nil
+}
+
+// prettyNode the parsed JSON value, keep the raw scalars and the keys order
+type prettyNode struct {
+	kind jsoniter.ValueType
+	// raw bytes of the scalar value
+	raw   []byte
+	keys  []string
+	elems []*prettyNode
+}
+
+func readPrettyNode(iter *jsoniter.Iterator) *prettyNode {
+	n := &prettyNode{kind: iter.WhatIsNext()}
+	switch n.kind {
+	case jsoniter.ObjectValue:
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			n.keys = append(n.keys, key)
+			n.elems = append(n.elems, readPrettyNode(iter))
+			return true
+		})
+	case jsoniter.ArrayValue:
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			n.elems = append(n.elems, readPrettyNode(iter))
+			return true
+		})
+	case jsoniter.InvalidValue:
+		iter.ReportError("Format", "invalid JSON value")
+	default:
+		n.raw = iter.SkipAndReturnBytes()
+	}
+	return n
+}
+
+// check the array can be printed in one line
+func (n *prettyNode) inline() bool {
+	for _, elem := range n.elems {
+		if elem.kind == jsoniter.ObjectValue || elem.kind == jsoniter.ArrayValue && !elem.inline() {
+			return false
+		}
+	}
+	return true
+}
+
+// width of the node printed in one line
+func (n *prettyNode) width() int {
+	if n.kind != jsoniter.ArrayValue {
+		return utf8.RuneCount(n.raw)
+	}
+
+	w := 2 // "[]"
+	for i, elem := range n.elems {
+		if i > 0 {
+			w += 2 // ", "
+		}
+		w += elem.width()
+	}
+	return w
+}
+
+type prettyPrinter struct {
+	opts *Options
+	buf  bytes.Buffer
+}
+
+// write the node, col is the width of the current line except the node.
+func (p *prettyPrinter) write(n *prettyNode, depth, col int) {
+	switch n.kind {
+	case jsoniter.ObjectValue:
+		p.writeObject(n, depth)
+	case jsoniter.ArrayValue:
+		if len(n.elems) == 0 {
+			p.buf.WriteString("[]")
+			return
+		}
+
+		if p.opts.MaxWidth > 0 && n.inline() && col+n.width() <= p.opts.MaxWidth {
+			p.writeInline(n)
+			return
+		}
+
+		p.buf.WriteString("[\n")
+		for i, elem := range n.elems {
+			p.writeIndent(depth + 1)
+			p.write(elem, depth+1, p.lineWidth(depth+1, 0, i, len(n.elems)))
+			p.writeEnd(i, len(n.elems))
+		}
+		p.writeIndent(depth)
+		p.buf.WriteByte(']')
+	default:
+		p.writeScalar(n.raw)
+	}
+}
+
+func (p *prettyPrinter) writeObject(n *prettyNode, depth int) {
+	if len(n.keys) == 0 {
+		p.buf.WriteString("{}")
+		return
+	}
+
+	idx := make([]int, len(n.keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	if p.opts.SortKeys {
+		sort.SliceStable(idx, func(i, j int) bool {
+			return n.keys[idx[i]] < n.keys[idx[j]]
+		})
+	}
+
+	p.buf.WriteString("{\n")
+	for i, ki := range idx {
+		key, _ := parser.MarshalToString(n.keys[ki])
+
+		p.writeIndent(depth + 1)
+		p.writeColored(p.opts.Theme.Key, key)
+		p.buf.WriteString(": ")
+
+		// +2 for the ": "
+		p.write(n.elems[ki], depth+1, p.lineWidth(depth+1, utf8.RuneCountInString(key)+2, i, len(idx)))
+		p.writeEnd(i, len(idx))
+	}
+	p.writeIndent(depth)
+	p.buf.WriteByte('}')
+}
+
+// write the array in one line. eg: [1, "a", [true, null]]
+func (p *prettyPrinter) writeInline(n *prettyNode) {
+	p.buf.WriteByte('[')
+	for i, elem := range n.elems {
+		if i > 0 {
+			p.buf.WriteString(", ")
+		}
+
+		if elem.kind == jsoniter.ArrayValue {
+			p.writeInline(elem)
+		} else {
+			p.writeScalar(elem.raw)
+		}
+	}
+	p.buf.WriteByte(']')
+}
+
+func (p *prettyPrinter) writeScalar(raw []byte) {
+	theme := p.opts.Theme
+
+	var code string
+	switch raw[0] {
+	case '"':
+		code = theme.String
+	case 't', 'f':
+		code = theme.Bool
+	case 'n':
+		code = theme.Null
+	default:
+		code = theme.Number
+	}
+	p.writeColored(code, string(raw))
+}
+
+func (p *prettyPrinter) writeColored(code, str string) {
+	if p.opts.Color && code != "" {
+		str = fmt.Sprintf(color.FullColorTpl, code, str)
+	}
+	p.buf.WriteString(str)
+}
+
+func (p *prettyPrinter) writeIndent(depth int) {
+	p.buf.WriteString(strings.Repeat(p.opts.Indent, depth))
+}
+
+// width of the indent, the key and the "," for the i-th element
+func (p *prettyPrinter) lineWidth(depth, keyLen, i, total int) int {
+	w := len(p.opts.Indent)*depth + keyLen
+	if i < total-1 {
+		w++
+	}
+	return w
+}
+
+func (p *prettyPrinter) writeEnd(i, total int) {
+	if i < total-1 {
+		p.buf.WriteByte(',')
+	}
+	p.buf.WriteByte('\n')
+}
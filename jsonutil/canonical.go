@@ -0,0 +1,203 @@
+package jsonutil
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// hash algorithms for Hash()
+const (
+	HashMD5    = "md5"
+	HashSHA1   = "sha1"
+	HashSHA256 = "sha256"
+)
+
+var hashFuncs = map[string]func() hash.Hash{
+	HashMD5:    md5.New,
+	HashSHA1:   sha1.New,
+	HashSHA256: sha256.New,
+}
+
+// Canonical encode the value to the canonical JSON. see RFC 8785 JSON Canonicalization Scheme(JCS)
+//
+// 	- the object keys are sorted by the UTF-16 code units
+// 	- the numbers are formatted as ECMAScript. eg: 1.0 => 1, 1e21 => 1e+21
+// 	- the strings are minimal escaped, no insignificant whitespace
+//
+// Usage:
+// 	bs, err := jsonutil.Canonical(map[string]interface{}{"b": 1.0, "a": "x"})
+// 	// bs: {"a":"x","b":1}
+func Canonical(v interface{}) ([]byte, error) {
+	bs, err := Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return CanonicalBytes(bs)
+}
+
+// CanonicalBytes convert the JSON bytes to the canonical JSON. see Canonical()
+//
+// NOTICE: the input must be a single JSON value, the data after the top-level value will return error.
+func CanonicalBytes(data []byte) ([]byte, error) {
+	doc, err := decodeDoc(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode document: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := writeCanonical(buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Hash get the hash string of the canonical JSON of the value. algo: md5, sha1, sha256
+//
+// Usage:
+// 	key, err := jsonutil.Hash(params, jsonutil.HashSHA256)
+func Hash(v interface{}, algo string) (string, error) {
+	newFn, ok := hashFuncs[algo]
+	if !ok {
+		return "", fmt.Errorf("jsonutil: unsupported hash algorithm %q", algo)
+	}
+
+	bs, err := Canonical(v)
+	if err != nil {
+		return "", err
+	}
+	h := newFn()
+	h.Write(bs)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch tv := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(tv))
+	case string:
+		writeCanonicalString(buf, tv)
+	case json.Number:
+		f, err := tv.Float64()
+		if err != nil || math.IsInf(f, 0) {
+			return fmt.Errorf("jsonutil: the number %s can not be canonicalized", tv)
+		}
+		buf.WriteString(es6Number(f))
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range tv {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := sortedKeys(tv)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return utf16Less(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, tv[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("jsonutil: invalid JSON value type %T", v)
+	}
+	return nil
+}
+
+// compare the strings by the UTF-16 code units
+func utf16Less(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// write the string with the minimal escaping
+func writeCanonicalString(buf *bytes.Buffer, str string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(str); {
+		c := str[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(str[i:])
+			buf.WriteRune(r)
+			i += size
+			continue
+		}
+
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, c)
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
+
+// format the number as the ECMAScript Number.prototype.toString()
+func es6Number(f float64) string {
+	if f == 0 {
+		return "0" // and the -0
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	str := strconv.FormatFloat(f, format, -1, 64)
+	if format == 'e' {
+		// clean up the exponent. eg: 1e-07 => 1e-7
+		n := len(str)
+		if n >= 4 && str[n-4] == 'e' && str[n-3] == '-' && str[n-2] == '0' {
+			str = str[:n-2] + str[n-1:]
+		}
+	}
+	return str
+}
@@ -0,0 +1,86 @@
+package jsonutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/jsonutil"
+	"github.com/urionz/goutil/strutil"
+)
+
+func TestCanonicalBytes(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		in, want string
+	}{
+		{`{ "b": 2, "a": 1 }`, `{"a":1,"b":2}`},
+		{`[1.0, 1e21, 1e-7, -0, 0.000001, 123456789012345678]`, `[1,1e+21,1e-7,0,0.000001,123456789012345680]`},
+		{`{"a":{"z":null,"y":[true,false]}}`, `{"a":{"y":[true,false],"z":null}}`},
+		{`"<a&b>é\u0001\n\"\\/"`, `"<a&b>é\u0001\n\"\\/"`},
+		// sorted by UTF-16 code units
+		{`{"\ufb33":2,"\ud83d\ude00":1,"a":3}`, "{\"a\":3,\"\U0001F600\":1,\"\uFB33\":2}"},
+	}
+
+	for _, tt := range tests {
+		bs, err := jsonutil.CanonicalBytes([]byte(tt.in))
+		is.NoError(err, tt.in)
+		is.Equal(tt.want, string(bs), tt.in)
+	}
+
+	_, err := jsonutil.CanonicalBytes([]byte(`[1e400]`))
+	is.Error(err)
+	_, err = jsonutil.CanonicalBytes([]byte(`{invalid`))
+	is.Error(err)
+
+	// the data after the top-level value
+	_, err = jsonutil.CanonicalBytes([]byte(`{"a":1}{"b":2}`))
+	is.Error(err)
+	_, err = jsonutil.CanonicalBytes([]byte(`[1] 2`))
+	is.Error(err)
+}
+
+func TestCanonical(t *testing.T) {
+	is := assert.New(t)
+
+	type user struct {
+		Name string  `json:"name"`
+		Age  int     `json:"age"`
+		Rate float64 `json:"rate"`
+	}
+
+	bs, err := jsonutil.Canonical(user{Name: "tom", Age: 20, Rate: 2.50})
+	is.NoError(err)
+	is.Equal(`{"age":20,"name":"tom","rate":2.5}`, string(bs))
+
+	bs1, err := jsonutil.Canonical(map[string]interface{}{"name": "tom", "rate": 2.5, "age": 20.0})
+	is.NoError(err)
+	is.Equal(string(bs), string(bs1))
+
+	_, err = jsonutil.Canonical(make(chan int))
+	is.Error(err)
+}
+
+func TestHash(t *testing.T) {
+	is := assert.New(t)
+
+	v1 := map[string]interface{}{"a": 1, "b": []int{1, 2}}
+	v2 := map[string]interface{}{"b": []float64{1.0, 2.0}, "a": 1.0}
+
+	h1, err := jsonutil.Hash(v1, jsonutil.HashSHA256)
+	is.NoError(err)
+	h2, err := jsonutil.Hash(v2, jsonutil.HashSHA256)
+	is.NoError(err)
+	is.Equal(h1, h2)
+	is.Equal(strutil.Sha256(`{"a":1,"b":[1,2]}`), h1)
+
+	h1, err = jsonutil.Hash(v1, jsonutil.HashMD5)
+	is.NoError(err)
+	is.Len(h1, 32)
+	h1, err = jsonutil.Hash(v1, jsonutil.HashSHA1)
+	is.NoError(err)
+	is.Len(h1, 40)
+
+	_, err = jsonutil.Hash(v1, "crc32")
+	is.Error(err)
+}
@@ -0,0 +1,585 @@
+package jsonutil
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError the JSON5 syntax error, with the position of the error.
+type SyntaxError struct {
+	Msg string
+	// Offset the byte offset of the error, start from 0
+	Offset int
+	// Line and Column of the error, start from 1
+	Line, Column int
+}
+
+// Error message. eg: "jsonutil: syntax error at line 2, column 5: invalid character 'x'"
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jsonutil: syntax error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// DecodeJSON5 decode JSON5 or JSONC(JSON with comments) bytes to data.
+//
+// Supported JSON5 features:
+// 	- single line and multi line comments
+// 	- trailing commas in objects and arrays
+// 	- unquoted object keys, single-quoted strings
+// 	- hex numbers, leading or trailing decimal point, explicit plus sign
+// 	- multi line strings by escape the line break
+//
+// NOTICE: Infinity and NaN are not supported, they can not be represented by JSON.
+func DecodeJSON5(src []byte, v interface{}) error {
+	bs, err := JSON5ToJSON(src)
+	if err != nil {
+		return err
+	}
+	return Decode(bs, v)
+}
+
+// JSON5ToJSON convert JSON5 or JSONC bytes to the standard JSON bytes.
+// returns *SyntaxError on the source is invalid.
+func JSON5ToJSON(src []byte) ([]byte, error) {
+	p := &json5Parser{src: src}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.buf.Bytes(), nil
+}
+
+type json5Parser struct {
+	src []byte
+	pos int
+	buf bytes.Buffer
+}
+
+func (p *json5Parser) parse() error {
+	if err := p.skipSpaces(); err != nil {
+		return err
+	}
+
+	if p.pos >= len(p.src) {
+		return p.errorf("unexpected end of input")
+	}
+
+	if err := p.parseValue(); err != nil {
+		return err
+	}
+
+	if err := p.skipSpaces(); err != nil {
+		return err
+	}
+
+	if p.pos < len(p.src) {
+		return p.errorf("invalid character %q after top-level value", p.peekRune())
+	}
+	return nil
+}
+
+// new syntax error at the current position
+func (p *json5Parser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *json5Parser) errorAt(pos int, msg string) error {
+	line, col := 1, 1
+	for _, r := range string(p.src[:pos]) {
+		if r == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return &SyntaxError{Msg: msg, Offset: pos, Line: line, Column: col}
+}
+
+func (p *json5Parser) peekRune() rune {
+	r, _ := utf8.DecodeRune(p.src[p.pos:])
+	return r
+}
+
+// skip the whitespaces and comments
+func (p *json5Parser) skipSpaces() error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			p.pos++
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/':
+			end := bytes.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end + 1
+			}
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*':
+			end := bytes.Index(p.src[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(p.src[p.pos:])
+			if !unicode.IsSpace(r) && r != '\uFEFF' {
+				return nil
+			}
+			p.pos += size
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *json5Parser) parseValue() error {
+	if p.pos >= len(p.src) {
+		return p.errorf("unexpected end of input")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+
+	start := p.pos
+	word := p.readIdent()
+	switch word {
+	case "true", "false", "null":
+		p.buf.WriteString(word)
+		return nil
+	case "Infinity", "NaN":
+		return p.errorAt(start, word+" is not supported")
+	case "":
+		return p.errorf("invalid character %q looking for beginning of value", p.peekRune())
+	}
+	return p.errorAt(start, fmt.Sprintf("invalid literal %q", word))
+}
+
+func (p *json5Parser) parseObject() error {
+	p.pos++ // skip "{"
+	p.buf.WriteByte('{')
+
+	for n := 0; ; n++ {
+		if err := p.skipSpaces(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) {
+			return p.errorf("unterminated object")
+		}
+
+		if p.src[p.pos] == '}' {
+			break
+		}
+
+		if n > 0 {
+			p.buf.WriteByte(',')
+		}
+
+		// object key
+		switch c := p.src[p.pos]; {
+		case c == '"' || c == '\'':
+			if err := p.parseString(); err != nil {
+				return err
+			}
+		default:
+			key := p.readIdent()
+			if key == "" {
+				return p.errorf("invalid character %q looking for object key", p.peekRune())
+			}
+			p.buf.WriteString(strconv.Quote(key))
+		}
+
+		if err := p.skipSpaces(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return p.errorf("missing ':' after object key")
+		}
+
+		p.pos++
+		p.buf.WriteByte(':')
+		if err := p.skipSpaces(); err != nil {
+			return err
+		}
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+
+		if !p.listMore() {
+			break
+		}
+	}
+
+	if err := p.expect('}', "object"); err != nil {
+		return err
+	}
+	p.buf.WriteByte('}')
+	return nil
+}
+
+func (p *json5Parser) parseArray() error {
+	p.pos++ // skip "["
+	p.buf.WriteByte('[')
+
+	for n := 0; ; n++ {
+		if err := p.skipSpaces(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) {
+			return p.errorf("unterminated array")
+		}
+
+		if p.src[p.pos] == ']' {
+			break
+		}
+
+		if n > 0 {
+			p.buf.WriteByte(',')
+		}
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+
+		if !p.listMore() {
+			break
+		}
+	}
+
+	if err := p.expect(']', "array"); err != nil {
+		return err
+	}
+	p.buf.WriteByte(']')
+	return nil
+}
+
+// skip the comma after element, returns false on no comma.
+func (p *json5Parser) listMore() bool {
+	if p.skipSpaces() != nil {
+		return false
+	}
+
+	if p.pos < len(p.src) && p.src[p.pos] == ',' {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *json5Parser) expect(end byte, kind string) error {
+	if err := p.skipSpaces(); err != nil {
+		return err
+	}
+
+	if p.pos >= len(p.src) {
+		return p.errorf("unterminated %s", kind)
+	}
+	if p.src[p.pos] != end {
+		return p.errorf("invalid character %q after %s element, expect ',' or '%c'", p.peekRune(), kind, end)
+	}
+
+	p.pos++
+	return nil
+}
+
+// read the identifier. eg: true, null, unquoted object key
+func (p *json5Parser) readIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRune(p.src[p.pos:])
+		isStart := r == '_' || r == '$' || unicode.IsLetter(r)
+		if !isStart && (p.pos == start || !(unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r))) {
+			break
+		}
+		p.pos += size
+	}
+	return string(p.src[start:p.pos])
+}
+
+// parse the double or single quoted string, write as the double quoted string.
+func (p *json5Parser) parseString() error {
+	start := p.pos
+	quote := p.src[p.pos]
+	p.pos++
+	p.buf.WriteByte('"')
+
+	for {
+		if p.pos >= len(p.src) {
+			return p.errorAt(start, "unterminated string")
+		}
+
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			p.buf.WriteByte('"')
+			return nil
+		case c == '\n' || c == '\r':
+			return p.errorAt(start, "unterminated string")
+		case c < 0x20:
+			fmt.Fprintf(&p.buf, `\u%04x`, c)
+			p.pos++
+		case c == '"': // in single-quoted string
+			p.buf.WriteString(`\"`)
+			p.pos++
+		case c == '\\':
+			if err := p.parseEscape(); err != nil {
+				return err
+			}
+		default:
+			p.buf.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *json5Parser) parseEscape() error {
+	start := p.pos
+	p.pos++ // skip "\"
+	if p.pos >= len(p.src) {
+		return p.errorAt(start, "unterminated string")
+	}
+
+	c := p.src[p.pos]
+	p.pos++
+	switch c {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		p.buf.WriteByte('\\')
+		p.buf.WriteByte(c)
+	case '\'':
+		p.buf.WriteByte('\'')
+	case 'v':
+		p.buf.WriteString(`\u000b`)
+	case '0':
+		if p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			return p.errorAt(start, "invalid octal escape")
+		}
+		p.buf.WriteString(`\u0000`)
+	case 'x', 'u':
+		n := 2
+		if c == 'u' {
+			n = 4
+		}
+
+		hex := p.src[p.pos:]
+		if len(hex) < n {
+			return p.errorAt(start, "invalid escape sequence")
+		}
+		if _, err := strconv.ParseUint(string(hex[:n]), 16, 16); err != nil {
+			return p.errorAt(start, "invalid escape sequence")
+		}
+
+		p.buf.WriteString(`\u`)
+		if c == 'x' {
+			p.buf.WriteString("00")
+		}
+		p.buf.Write(hex[:n])
+		p.pos += n
+	case '\n': // line continuation
+	case '\r':
+		if p.pos < len(p.src) && p.src[p.pos] == '\n' {
+			p.pos++
+		}
+	default:
+		if c >= '1' && c <= '9' {
+			return p.errorAt(start, "invalid octal escape")
+		}
+
+		// other chars are escaped to itself
+		p.pos--
+		r, size := utf8.DecodeRune(p.src[p.pos:])
+		p.pos += size
+		switch {
+		case r < 0x20: // the control char must be escaped in JSON
+			fmt.Fprintf(&p.buf, `\u%04x`, r)
+		case r != '\u2028' && r != '\u2029':
+			p.buf.WriteRune(r)
+		}
+	}
+	return nil
+}
+
+func (p *json5Parser) parseNumber() error {
+	start := p.pos
+	c := p.src[p.pos]
+	if c == '+' || c == '-' {
+		if c == '-' {
+			p.buf.WriteByte('-')
+		}
+		p.pos++
+	}
+
+	// Infinity with sign
+	if p.pos < len(p.src) && (p.src[p.pos] == 'I' || p.src[p.pos] == 'N') {
+		return p.errorAt(start, p.readIdent()+" is not supported")
+	}
+
+	// hex number
+	if p.pos+1 < len(p.src) && p.src[p.pos] == '0' && (p.src[p.pos+1] == 'x' || p.src[p.pos+1] == 'X') {
+		p.pos += 2
+		digits := p.readWhile(isHexDigit)
+
+		n, err := strconv.ParseUint(digits, 16, 64)
+		if err != nil {
+			return p.errorAt(start, fmt.Sprintf("invalid hex number %q", string(p.src[start:p.pos])))
+		}
+		p.buf.WriteString(strconv.FormatUint(n, 10))
+		return p.checkNumberEnd(start)
+	}
+
+	intPart := p.readWhile(isDigit)
+	if len(intPart) > 1 && intPart[0] == '0' {
+		return p.errorAt(start, "invalid number with leading zero")
+	}
+
+	var frac string
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		frac = p.readWhile(isDigit)
+	}
+
+	if intPart == "" && frac == "" {
+		return p.errorAt(start, fmt.Sprintf("invalid number %q", string(p.src[start:p.pos])))
+	}
+
+	// ".5" => "0.5", "5." => "5"
+	if intPart == "" {
+		intPart = "0"
+	}
+	p.buf.WriteString(intPart)
+	if frac != "" {
+		p.buf.WriteString("." + frac)
+	}
+
+	// exponent
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		p.pos++
+		p.buf.WriteByte('e')
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.buf.WriteByte(p.src[p.pos])
+			p.pos++
+		}
+
+		digits := p.readWhile(isDigit)
+		if digits == "" {
+			return p.errorAt(start, fmt.Sprintf("invalid number %q", string(p.src[start:p.pos])))
+		}
+		p.buf.WriteString(digits)
+	}
+	return p.checkNumberEnd(start)
+}
+
+// the number must not followed by the identifier chars. eg: "12ab"
+func (p *json5Parser) checkNumberEnd(start int) error {
+	if p.pos < len(p.src) {
+		r := p.peekRune()
+		if r == '_' || r == '$' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return p.errorAt(start, fmt.Sprintf("invalid character %q in number", r))
+		}
+	}
+	return nil
+}
+
+func (p *json5Parser) readWhile(fn func(c byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.src) && fn(p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// StripComments strip comments for a JSON string. the comment chars in the strings are kept.
+//
+// NOTICE: if there are single line comments, the whitespaces will be removed too.
+// use JSON5ToJSON() for parse the JSONC or JSON5 strictly.
+func StripComments(src string) string {
+	minify := hasLineComment(src)
+
+	var sb strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := stringEnd(src, i)
+			sb.WriteString(src[i:end])
+			i = end
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 4
+			}
+
+			// skip the whitespaces after the comment
+			i += end + 4
+			for i < len(src) && isSpace(src[i]) {
+				i++
+			}
+		case minify && isSpace(c):
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// check there are single line comments out of strings
+func hasLineComment(src string) bool {
+	for i := 0; i < len(src); i++ {
+		switch {
+		case src[i] == '"' || src[i] == '\'':
+			i = stringEnd(src, i) - 1
+		case strings.HasPrefix(src[i:], "/*"):
+			if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				return false
+			}
+		case strings.HasPrefix(src[i:], "//"):
+			return true
+		}
+	}
+	return false
+}
+
+// find the end position of the quoted string start at the pos, after the close quote.
+func stringEnd(src string, pos int) int {
+	quote := src[pos]
+	for i := pos + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote, '\n':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,109 @@
+package jsonutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/jsonutil"
+)
+
+func TestJSON5ToJSON(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		src, want string
+	}{
+		{`{"name": "app"}`, `{"name":"app"}`},
+		{" \n[1, 2, 3]\n ", `[1,2,3]`},
+		// comments
+		{"{\n// comment\n\"a\": 1 // comment\n}", `{"a":1}`},
+		{`/* comment */ {"a": /* comment */ 1} /* comment */`, `{"a":1}`},
+		{`{"url": "http://host/*path*/", 'b': '// no comment'}`, `{"url":"http://host/*path*/","b":"// no comment"}`},
+		// trailing commas
+		{`{"a": 1, "b": [1, 2,],}`, `{"a":1,"b":[1,2]}`},
+		// unquoted keys
+		{`{name: 'app', $id: 1, _key2: true, 名称: null}`, `{"name":"app","$id":1,"_key2":true,"名称":null}`},
+		// single quoted strings
+		{`['it\'s', 'say "hi"', "it's"]`, `["it's","say \"hi\"","it's"]`},
+		{`['\x41\v\0', "line1 \
+line2"]`, `["\u0041\u000b\u0000","line1 line2"]`},
+		{`['a\qb', '中']`, `["aqb","中"]`},
+		{"['a\\\tb', 'c\\\x01d']", `["a\u0009b","c\u0001d"]`},
+		// numbers
+		{`[0x1F, -0XFF, +1, .5, 5., 1e3, -1.5E-2, 0]`, `[31,-255,1,0.5,5,1e3,-1.5e-2,0]`},
+	}
+
+	for _, tt := range tests {
+		got, err := jsonutil.JSON5ToJSON([]byte(tt.src))
+		is.NoError(err, tt.src)
+		is.Equal(tt.want, string(got), tt.src)
+	}
+}
+
+func TestJSON5ToJSON_error(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		src       string
+		line, col int
+		msg       string
+	}{
+		{``, 1, 1, "unexpected end of input"},
+		{`{"a": 1`, 1, 8, "unterminated object"},
+		{"{\n  \"a\" 1\n}", 2, 7, "missing ':'"},
+		{"[1,\n 2\n 3]", 3, 2, "expect ',' or ']'"},
+		{`{"a": 'abc}`, 1, 7, "unterminated string"},
+		{"{\"a\": \"ab\nc\"}", 1, 7, "unterminated string"},
+		{`[1, /* comment`, 1, 5, "unterminated comment"},
+		{`[Infinity]`, 1, 2, "Infinity is not supported"},
+		{`[-NaN]`, 1, 2, "NaN is not supported"},
+		{`[01]`, 1, 2, "leading zero"},
+		{`[0xZ]`, 1, 2, "invalid hex number"},
+		{`[12ab]`, 1, 2, "invalid character"},
+		{`[1e]`, 1, 2, "invalid number"},
+		{`[undefined]`, 1, 2, "invalid literal"},
+		{`{1: 2}`, 1, 2, "looking for object key"},
+		{`[1] 2`, 1, 5, "after top-level value"},
+		{`['\1']`, 1, 3, "invalid octal escape"},
+		{`['\xZZ']`, 1, 3, "invalid escape sequence"},
+		{"[\n 中文]", 2, 2, "invalid literal"},
+		{`[}]`, 1, 2, "looking for beginning of value"},
+	}
+
+	for _, tt := range tests {
+		_, err := jsonutil.JSON5ToJSON([]byte(tt.src))
+		se, ok := err.(*jsonutil.SyntaxError)
+		if !is.True(ok, tt.src) {
+			continue
+		}
+
+		is.Equal(tt.line, se.Line, tt.src)
+		is.Equal(tt.col, se.Column, tt.src)
+		is.Contains(se.Error(), tt.msg, tt.src)
+	}
+}
+
+func TestDecodeJSON5(t *testing.T) {
+	is := assert.New(t)
+
+	user := struct {
+		Name string   `json:"name"`
+		Age  int      `json:"age"`
+		Tags []string `json:"tags"`
+	}{}
+
+	err := jsonutil.DecodeJSON5([]byte(`{name: 'inhere', age: 22, tags: ['a',],}`), &user)
+	is.NoError(err)
+	is.Equal("inhere", user.Name)
+	is.Equal(22, user.Age)
+	is.Equal([]string{"a"}, user.Tags)
+
+	is.Error(jsonutil.DecodeJSON5([]byte(`{name: }`), &user))
+
+	// read file
+	err = jsonutil.ReadFile("testdata/test.json5", &user)
+	is.NoError(err)
+	is.Equal("inhere", user.Name)
+	is.Equal(200, user.Age)
+	is.Equal([]string{"a", "b"}, user.Tags)
+}
@@ -1,13 +1,10 @@
 package jsonutil
 
 import (
-	"bytes"
-	"encoding/json"
 	"io/ioutil"
 	"os"
-	"regexp"
+	"path/filepath"
 	"strings"
-	"text/scanner"
 
 	"github.com/json-iterator/go"
 )
@@ -23,7 +20,7 @@ func WriteFile(filePath string, data interface{}) error {
 	return ioutil.WriteFile(filePath, jsonBytes, 0664)
 }
 
-// ReadFile Read JSON file data
+// ReadFile Read JSON file data. the file with ext ".jsonc" or ".json5" will be decoded as JSON5.
 func ReadFile(filePath string, v interface{}) error {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -35,6 +32,11 @@ func ReadFile(filePath string, v interface{}) error {
 	if err != nil {
 		return err
 	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".jsonc", ".json5":
+		return DecodeJSON5(content, v)
+	}
 	return Decode(content, v)
 }
 
@@ -47,43 +49,3 @@ func Encode(v interface{}) ([]byte, error) {
 func Decode(json []byte, v interface{}) error {
 	return parser.Unmarshal(json, v)
 }
-
-// Pretty get pretty JSON string
-func Pretty(v interface{}) (string, error) {
-	out, err := json.MarshalIndent(v, "", "    ")
-	return string(out), err
-}
-
-// `(?s:` enable match multi line
-var jsonMLComments = regexp.MustCompile(`(?s:/\*.*?\*/\s*)`)
-
-// StripComments strip comments for a JSON string
-func StripComments(src string) string {
-	// multi line comments
-	if strings.Contains(src, "/*") {
-		src = jsonMLComments.ReplaceAllString(src, "")
-	}
-
-	// single line comments
-	if !strings.Contains(src, "//") {
-		return strings.TrimSpace(src)
-	}
-
-	var s scanner.Scanner
-
-	s.Init(strings.NewReader(src))
-	s.Filename = "comments"
-	s.Mode ^= scanner.SkipComments // don't skip comments
-
-	buf := new(bytes.Buffer)
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		txt := s.TokenText()
-		if !strings.HasPrefix(txt, "//") && !strings.HasPrefix(txt, "/*") {
-			buf.WriteString(txt)
-			// } else {
-			// fmt.Printf("%s: %s\n", s.Position, txt)
-		}
-	}
-
-	return buf.String()
-}
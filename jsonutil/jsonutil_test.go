@@ -1,6 +1,8 @@
 package jsonutil_test
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,6 +40,12 @@ func TestWriteReadFile(t *testing.T) {
 
 	assert.Equal(t, "inhere", user.Name)
 	assert.Equal(t, 200, user.Age)
+
+	// the trailing data is invalid
+	err = ioutil.WriteFile("testdata/trailing.json", []byte(`{"name": "tom"} {}`), 0664)
+	assert.NoError(t, err)
+	defer os.Remove("testdata/trailing.json")
+	assert.Error(t, jsonutil.ReadFile("testdata/trailing.json", &user))
 }
 
 func TestStripComments(t *testing.T) {
@@ -167,3 +175,13 @@ comments
 	s = jsonutil.StripComments(s)
 	assert.Equal(t, ep, s)
 }
+
+func TestStripComments_inString(t *testing.T) {
+	is := assert.New(t)
+
+	str := jsonutil.StripComments(`{"url": "http://host/*path*/"} /* comments */`)
+	is.Equal(`{"url": "http://host/*path*/"}`, str)
+
+	str = jsonutil.StripComments("{\"url\": \"http://host\" // comments\n}")
+	is.Equal(`{"url":"http://host"}`, str)
+}
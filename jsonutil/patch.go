@@ -0,0 +1,629 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/json-iterator/go"
+)
+
+// operations of the JSON Patch
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// the parser for decode JSON document, same as the parser but use json.Number for the numbers.
+var docParser = jsoniter.Config{
+	EscapeHTML:             true,
+	SortMapKeys:            true,
+	ValidateJsonRawMessage: true,
+	UseNumber:              true,
+}.Froze()
+
+// decode JSON document, the numbers will be decoded as json.Number for keep the precision.
+func decodeDoc(data []byte) (interface{}, error) {
+	iter := docParser.BorrowIterator(data)
+	defer docParser.ReturnIterator(iter)
+
+	var doc interface{}
+	iter.ReadVal(&doc)
+
+	// reject the data after the top-level value. eg: {"a":1} garbage
+	if iter.Error == nil {
+		iter.WhatIsNext()
+		if iter.Error == nil {
+			iter.ReportError("decodeDoc", "unexpected data after the top-level value")
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	return doc, nil
+}
+
+//
+// ------------------ JSON Merge Patch(RFC 7396) ------------------
+//
+
+// MergePatch apply the JSON merge patch to the document. see RFC 7396
+//
+// Usage:
+// 	doc, err := jsonutil.MergePatch(
+// 		[]byte(`{"name": "app", "debug": true}`),
+// 		[]byte(`{"debug": null, "port": 8080}`),
+// 	)
+// 	// doc: {"name":"app","port":8080}
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decodeDoc(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode document: %w", err)
+	}
+
+	p, err := decodeDoc(patch)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode merge patch: %w", err)
+	}
+	return Encode(mergePatch(target, p))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = make(map[string]interface{}, len(pm))
+	}
+
+	for key, val := range pm {
+		if val == nil {
+			delete(tm, key)
+		} else {
+			tm[key] = mergePatch(tm[key], val)
+		}
+	}
+	return tm
+}
+
+// CreateMergePatch create the JSON merge patch, it can change the original to the modified.
+//
+// NOTICE: the merge patch can not set a value to null, the null means remove.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	a, err := decodeDoc(original)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode original document: %w", err)
+	}
+
+	b, err := decodeDoc(modified)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode modified document: %w", err)
+	}
+	return Encode(createMergePatch(a, b))
+}
+
+func createMergePatch(a, b interface{}) interface{} {
+	am, ok1 := a.(map[string]interface{})
+	bm, ok2 := b.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return b
+	}
+
+	patch := make(map[string]interface{})
+	for key := range am {
+		if _, ok := bm[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	for key, bv := range bm {
+		av, ok := am[key]
+		if !ok {
+			patch[key] = bv
+			continue
+		}
+
+		if jsonEqual(av, bv) {
+			continue
+		}
+
+		_, isMap := bv.(map[string]interface{})
+		if _, avIsMap := av.(map[string]interface{}); isMap && avIsMap {
+			patch[key] = createMergePatch(av, bv)
+		} else {
+			patch[key] = bv
+		}
+	}
+	return patch
+}
+
+//
+// ------------------ JSON Patch(RFC 6902) ------------------
+//
+
+// PatchOp one operation of the JSON Patch
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON the value member only for the add, replace and test operations.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"op": op.Op, "path": op.Path}
+	switch op.Op {
+	case OpAdd, OpReplace, OpTest:
+		m["value"] = op.Value
+	case OpMove, OpCopy:
+		m["from"] = op.From
+	}
+	return Encode(m)
+}
+
+// UnmarshalJSON the operation, check the required members.
+func (op *PatchOp) UnmarshalJSON(data []byte) error {
+	doc, err := decodeDoc(data)
+	if err != nil {
+		return err
+	}
+
+	raw, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("the operation must be an object")
+	}
+
+	*op = PatchOp{}
+	for key, ptr := range map[string]*string{"op": &op.Op, "path": &op.Path, "from": &op.From} {
+		val, ok := raw[key]
+		if !ok {
+			continue
+		}
+
+		if *ptr, ok = val.(string); !ok {
+			return fmt.Errorf("the %s member must be a string", key)
+		}
+	}
+
+	if _, ok := raw["path"]; !ok {
+		return fmt.Errorf("missing the path member")
+	}
+
+	switch op.Op {
+	case OpAdd, OpReplace, OpTest:
+		if op.Value, ok = raw["value"]; !ok {
+			return fmt.Errorf("missing the value member for %q", op.Op)
+		}
+	case OpMove, OpCopy:
+		if _, ok := raw["from"]; !ok {
+			return fmt.Errorf("missing the from member for %q", op.Op)
+		}
+	}
+	return nil
+}
+
+// Patch the JSON Patch document, a list of operations. see RFC 6902
+type Patch []PatchOp
+
+// DecodePatch decode the JSON Patch document
+func DecodePatch(data []byte) (Patch, error) {
+	var p Patch
+	if err := Decode(data, &p); err != nil {
+		return nil, fmt.Errorf("jsonutil: decode JSON patch: %w", err)
+	}
+	return p, nil
+}
+
+// ApplyPatch apply the JSON Patch to the document. see RFC 6902
+//
+// Usage:
+// 	doc, err := jsonutil.ApplyPatch(
+// 		[]byte(`{"tags": ["a"]}`),
+// 		[]byte(`[{"op": "add", "path": "/tags/-", "value": "b"}]`),
+// 	)
+// 	// doc: {"tags":["a","b"]}
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	p, err := DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return p.Apply(doc)
+}
+
+// Apply the patch to the document. the operations are applied in order,
+// will stop and return error on any operation failed.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	root, err := decodeDoc(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode document: %w", err)
+	}
+
+	for i, op := range p {
+		if root, err = op.apply(root); err != nil {
+			return nil, fmt.Errorf("jsonutil: patch op #%d %s %q: %w", i, op.Op, op.Path, err)
+		}
+	}
+	return Encode(root)
+}
+
+func (op *PatchOp) apply(root interface{}) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case OpMove, OpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := getPointer(root, from)
+		if err != nil {
+			return nil, fmt.Errorf("from %q: %w", op.From, err)
+		}
+
+		if op.Op == OpCopy {
+			return addPointer(root, path, deepCopy(val))
+		}
+
+		// can not move to the children of itself
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("can not move to the children of %q", op.From)
+		}
+
+		if root, err = removePointer(root, from); err != nil {
+			return nil, err
+		}
+		return addPointer(root, path, val)
+	}
+
+	// copy the value, the patch can be applied multi times
+	switch op.Op {
+	case OpAdd:
+		return addPointer(root, path, deepCopy(op.Value))
+	case OpRemove:
+		return removePointer(root, path)
+	case OpReplace:
+		if _, err := getPointer(root, path); err != nil {
+			return nil, err
+		}
+
+		if len(path) == 0 {
+			return deepCopy(op.Value), nil
+		}
+
+		if root, err = removePointer(root, path); err != nil {
+			return nil, err
+		}
+		return addPointer(root, path, deepCopy(op.Value))
+	case OpTest:
+		val, err := getPointer(root, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !jsonEqual(val, op.Value) {
+			return nil, fmt.Errorf("test failed, the value is not equal")
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("invalid operation %q", op.Op)
+}
+
+// Diff generate the JSON Patch between two documents, apply it to the original will get the modified.
+func Diff(original, modified []byte) (Patch, error) {
+	a, err := decodeDoc(original)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode original document: %w", err)
+	}
+
+	b, err := decodeDoc(modified)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: decode modified document: %w", err)
+	}
+
+	p := Patch{}
+	diffPatch(&p, "", a, b)
+	return p, nil
+}
+
+func diffPatch(p *Patch, path string, a, b interface{}) {
+	if jsonEqual(a, b) {
+		return
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		for _, key := range sortedKeys(av) {
+			if _, ok := bv[key]; !ok {
+				*p = append(*p, PatchOp{Op: OpRemove, Path: path + "/" + escapePointer(key)})
+			}
+		}
+
+		for _, key := range sortedKeys(bv) {
+			sub := path + "/" + escapePointer(key)
+			if aVal, ok := av[key]; ok {
+				diffPatch(p, sub, aVal, bv[key])
+			} else {
+				*p = append(*p, PatchOp{Op: OpAdd, Path: sub, Value: bv[key]})
+			}
+		}
+		return
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+
+		i := 0
+		for ; i < len(av) && i < len(bv); i++ {
+			diffPatch(p, path+"/"+strconv.Itoa(i), av[i], bv[i])
+		}
+
+		for j := i; j < len(bv); j++ {
+			*p = append(*p, PatchOp{Op: OpAdd, Path: path + "/" + strconv.Itoa(j), Value: bv[j]})
+		}
+
+		// remove from the end, keep the index of the previous elements
+		for j := len(av) - 1; j >= i; j-- {
+			*p = append(*p, PatchOp{Op: OpRemove, Path: path + "/" + strconv.Itoa(j)})
+		}
+		return
+	}
+
+	*p = append(*p, PatchOp{Op: OpReplace, Path: path, Value: b})
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//
+// ------------------ JSON Pointer(RFC 6901) ------------------
+//
+
+// parse JSON pointer to the reference tokens. eg: "/a/b~1c" => ["a", "b/c"]
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q, must start with '/'", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func escapePointer(tok string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1")
+}
+
+// parse the array index token, the "-" is the index after the last element.
+// the index must be "0" or digits without leading zeros, the sign is not allowed.
+func arrayIndex(tok string, length int, allowEnd bool) (int, error) {
+	if tok == "-" && allowEnd {
+		return length, nil
+	}
+
+	if tok == "" || strings.Trim(tok, "0123456789") != "" || (tok != "0" && tok[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	idx, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	if idx > length || (idx == length && !allowEnd) {
+		return 0, fmt.Errorf("array index %d out of range", idx)
+	}
+	return idx, nil
+}
+
+func getPointer(node interface{}, tokens []string) (interface{}, error) {
+	for _, tok := range tokens {
+		switch tv := node.(type) {
+		case map[string]interface{}:
+			val, ok := tv[tok]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", tok)
+			}
+			node = val
+		case []interface{}:
+			idx, err := arrayIndex(tok, len(tv), false)
+			if err != nil {
+				return nil, err
+			}
+			node = tv[idx]
+		default:
+			return nil, fmt.Errorf("can not get %q from the scalar value", tok)
+		}
+	}
+	return node, nil
+}
+
+// add value by the pointer, returns the new node.
+func addPointer(node interface{}, tokens []string, val interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+
+	tok, rest := tokens[0], tokens[1:]
+	switch tv := node.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			tv[tok] = val
+			return tv, nil
+		}
+
+		child, ok := tv[tok]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", tok)
+		}
+
+		child, err := addPointer(child, rest, val)
+		if err != nil {
+			return nil, err
+		}
+		tv[tok] = child
+		return tv, nil
+	case []interface{}:
+		idx, err := arrayIndex(tok, len(tv), len(rest) == 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rest) == 0 {
+			tv = append(tv, nil)
+			copy(tv[idx+1:], tv[idx:])
+			tv[idx] = val
+			return tv, nil
+		}
+
+		if tv[idx], err = addPointer(tv[idx], rest, val); err != nil {
+			return nil, err
+		}
+		return tv, nil
+	}
+	return nil, fmt.Errorf("can not add %q to the scalar value", tok)
+}
+
+// remove value by the pointer, returns the new node.
+func removePointer(node interface{}, tokens []string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("can not remove the root document")
+	}
+
+	tok, rest := tokens[0], tokens[1:]
+	switch tv := node.(type) {
+	case map[string]interface{}:
+		child, ok := tv[tok]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", tok)
+		}
+
+		if len(rest) == 0 {
+			delete(tv, tok)
+			return tv, nil
+		}
+
+		child, err := removePointer(child, rest)
+		if err != nil {
+			return nil, err
+		}
+		tv[tok] = child
+		return tv, nil
+	case []interface{}:
+		idx, err := arrayIndex(tok, len(tv), false)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rest) == 0 {
+			return append(tv[:idx], tv[idx+1:]...), nil
+		}
+
+		if tv[idx], err = removePointer(tv[idx], rest); err != nil {
+			return nil, err
+		}
+		return tv, nil
+	}
+	return nil, fmt.Errorf("can not remove %q from the scalar value", tok)
+}
+
+func deepCopy(val interface{}) interface{} {
+	switch tv := val.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(tv))
+		for key, sub := range tv {
+			m[key] = deepCopy(sub)
+		}
+		return m
+	case []interface{}:
+		ls := make([]interface{}, len(tv))
+		for i, sub := range tv {
+			ls[i] = deepCopy(sub)
+		}
+		return ls
+	}
+	return val
+}
+
+// check two decoded JSON values is equal, the numbers are compared by value.
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for key, sub := range av {
+			bSub, ok := bv[key]
+			if !ok || !jsonEqual(sub, bSub) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number, float64:
+		if av == b {
+			return true
+		}
+
+		af, ok1 := jsonFloat(av)
+		bf, ok2 := jsonFloat(b)
+		return ok1 && ok2 && af == bf
+	}
+	return a == b
+}
+
+// get the float value of the decoded JSON number
+func jsonFloat(v interface{}) (float64, bool) {
+	switch tv := v.(type) {
+	case json.Number:
+		f, err := tv.Float64()
+		return f, err == nil
+	case float64:
+		return tv, true
+	}
+	return 0, false
+}
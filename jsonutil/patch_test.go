@@ -0,0 +1,196 @@
+package jsonutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/jsonutil"
+)
+
+func TestMergePatch(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"n":12345678901234567890}`, `{"m":1.5}`, `{"m":1.5,"n":12345678901234567890}`},
+	}
+
+	for _, tt := range tests {
+		got, err := jsonutil.MergePatch([]byte(tt.doc), []byte(tt.patch))
+		is.NoError(err)
+		is.JSONEq(tt.want, string(got), tt.doc+" + "+tt.patch)
+	}
+
+	_, err := jsonutil.MergePatch([]byte(`{`), []byte(`{}`))
+	is.Error(err)
+	_, err = jsonutil.MergePatch([]byte(`{}`), []byte(`{`))
+	is.Error(err)
+
+	// the data after the top-level value
+	_, err = jsonutil.MergePatch([]byte(`{"a":1} garbage`), []byte(`{}`))
+	is.Error(err)
+	is.Contains(err.Error(), "unexpected data after the top-level value")
+	_, err = jsonutil.MergePatch([]byte(`{"a":1}`), []byte("{}\n{}"))
+	is.Error(err)
+	_, err = jsonutil.ApplyPatch([]byte(`{"a":1}]`), []byte(`[]`))
+	is.Error(err)
+	_, err = jsonutil.Diff([]byte(`{}`), []byte(`[1] 2`))
+	is.Error(err)
+
+	got, err := jsonutil.MergePatch([]byte(" {\"a\":1}\n"), []byte(`{}`))
+	is.NoError(err)
+	is.Equal(`{"a":1}`, string(got))
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	is := assert.New(t)
+
+	a := `{"name":"app","debug":true,"db":{"host":"localhost","port":3306},"tags":["a"]}`
+	b := `{"name":"app","db":{"host":"127.0.0.1","port":3306},"tags":["a","b"],"env":"prod"}`
+
+	patch, err := jsonutil.CreateMergePatch([]byte(a), []byte(b))
+	is.NoError(err)
+	is.JSONEq(`{"debug":null,"db":{"host":"127.0.0.1"},"tags":["a","b"],"env":"prod"}`, string(patch))
+
+	got, err := jsonutil.MergePatch([]byte(a), patch)
+	is.NoError(err)
+	is.JSONEq(b, string(got))
+
+	patch, err = jsonutil.CreateMergePatch([]byte(a), []byte(a))
+	is.NoError(err)
+	is.Equal(`{}`, string(patch))
+}
+
+func TestApplyPatch(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		doc, patch, want string
+	}{
+		// add
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc"]}]`, `{"foo":["bar",["abc"]]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grand":"c"}}]`, `{"foo":"bar","child":{"grand":"c"}}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"","value":[1]}]`, `[1]`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`},
+		// remove
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		// replace
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":[1,2]}`, `[{"op":"replace","path":"/foo/0","value":3}]`, `{"foo":[3,2]}`},
+		{`{"foo":1}`, `[{"op":"replace","path":"","value":{"a":1}}]`, `{"a":1}`},
+		// move
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		// copy
+		{`{"foo":{"a":1}}`, `[{"op":"copy","from":"/foo","path":"/bar"},{"op":"add","path":"/bar/b","value":2}]`, `{"foo":{"a":1},"bar":{"a":1,"b":2}}`},
+		// test
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		// escaped keys
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+	}
+
+	for _, tt := range tests {
+		got, err := jsonutil.ApplyPatch([]byte(tt.doc), []byte(tt.patch))
+		is.NoError(err, tt.patch)
+		is.JSONEq(tt.want, string(got), tt.patch)
+	}
+
+	errTests := []struct {
+		doc, patch string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":1}]`},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/01","value":1}]`},
+		{`{"foo":[1]}`, `[{"op":"remove","path":"/foo/-"}]`},
+		{`{"foo":[1, 2]}`, `[{"op":"remove","path":"/foo/+1"}]`},
+		{`{"foo":[1, 2]}`, `[{"op":"remove","path":"/foo/-0"}]`},
+		{`{"foo":[1, 2]}`, `[{"op":"remove","path":"/foo/"}]`},
+		{`{"foo":"bar"}`, `[{"op":"move","path":"/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"copy","path":"/baz"}]`},
+		{`{"foo":{"a":1}}`, `[{"op":"move","from":"/foo","path":"/foo/a/b"}]`},
+		{`{"foo":"bar"}`, `[{"op":"test","path":"/foo","value":"baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"invalid","path":"/foo"}]`},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"foo"}]`},
+		{`{"foo":"bar"}`, `[{"op":"remove"}]`},
+		{`{"foo":"bar"}`, `{}`},
+		{`{`, `[]`},
+	}
+	for _, tt := range errTests {
+		_, err := jsonutil.ApplyPatch([]byte(tt.doc), []byte(tt.patch))
+		is.Error(err, tt.patch)
+	}
+	// the patch values are not changed by the later operations
+	p, err := jsonutil.DecodePatch([]byte(`[
+		{"op":"add","path":"/a","value":{"x":1}},
+		{"op":"remove","path":"/a/x"},
+		{"op":"replace","path":"/b","value":{"x":1}},
+		{"op":"remove","path":"/b/x"}
+	]`))
+	is.NoError(err)
+	for i := 0; i < 2; i++ {
+		doc, err := p.Apply([]byte(`{"b":null}`))
+		is.NoError(err)
+		is.JSONEq(`{"a":{},"b":{}}`, string(doc))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	is := assert.New(t)
+
+	a := `{"name":"app","debug":true,"db":{"host":"localhost","port":3306},"tags":["a","b","c"],"a/b":1}`
+	b := `{"name":"app","db":{"host":"127.0.0.1","port":3306.0},"tags":["a","x"],"env":null,"a/b":[1]}`
+
+	patch, err := jsonutil.Diff([]byte(a), []byte(b))
+	is.NoError(err)
+
+	bs, err := jsonutil.Encode(patch)
+	is.NoError(err)
+	is.JSONEq(`[
+		{"op":"remove","path":"/debug"},
+		{"op":"replace","path":"/a~1b","value":[1]},
+		{"op":"replace","path":"/db/host","value":"127.0.0.1"},
+		{"op":"add","path":"/env","value":null},
+		{"op":"replace","path":"/tags/1","value":"x"},
+		{"op":"remove","path":"/tags/2"}
+	]`, string(bs))
+
+	got, err := patch.Apply([]byte(a))
+	is.NoError(err)
+	is.JSONEq(b, string(got))
+
+	// the encoded patch can be decoded and applied
+	got, err = jsonutil.ApplyPatch([]byte(a), bs)
+	is.NoError(err)
+	is.JSONEq(b, string(got))
+
+	patch, err = jsonutil.Diff([]byte(a), []byte(a))
+	is.NoError(err)
+	is.Empty(patch)
+
+	patch, err = jsonutil.Diff([]byte(`[1]`), []byte(`[1,2,3]`))
+	is.NoError(err)
+	bs, err = jsonutil.Encode(patch)
+	is.NoError(err)
+	is.JSONEq(`[{"op":"add","path":"/1","value":2},{"op":"add","path":"/2","value":3}]`, string(bs))
+}
@@ -0,0 +1,75 @@
+package jsonutil
+
+import (
+	"fmt"
+
+	"github.com/urionz/color"
+	"github.com/urionz/goutil/internal/jsonpretty"
+)
+
+// PrettyOptions for format the JSON
+//
+// 	- Indent string for each level. default is 4 spaces
+// 	- SortKeys sort the object keys. default keep the original order
+// 	- MaxWidth if > 0, the array without object will be printed in one line
+// 	  when it fits the width. eg: "tags": [1, 2, 3]
+// 	- Color render the tokens with ANSI colors by the Theme
+// 	- Theme the colors for the tokens. default is DefaultTheme
+type PrettyOptions = jsonpretty.Options
+
+// ColorTheme the ANSI color codes for the JSON tokens. eg: color.FgGreen.Code()
+type ColorTheme = jsonpretty.Theme
+
+// DefaultTheme for the colored JSON output
+var DefaultTheme = &ColorTheme{
+	Key:    color.FgBlue.Code(),
+	String: color.FgGreen.Code(),
+	Number: color.FgYellow.Code(),
+	Bool:   color.FgMagenta.Code(),
+	Null:   color.FgDarkGray.Code(),
+}
+
+func newPrettyOptions(fns []func(opts *PrettyOptions)) *PrettyOptions {
+	opts := &PrettyOptions{Indent: "    "}
+	for _, fn := range fns {
+		fn(opts)
+	}
+
+	if opts.Theme == nil {
+		opts.Theme = DefaultTheme
+	}
+	return opts
+}
+
+// Pretty get pretty JSON string, indent by 4 spaces
+func Pretty(v interface{}) (string, error) {
+	return PrettyWith(v)
+}
+
+// PrettyWith get pretty JSON string with custom options
+//
+// Usage:
+// 	str, err := jsonutil.PrettyWith(v, func(opts *jsonutil.PrettyOptions) {
+// 		opts.Indent = "  "
+// 		opts.SortKeys = true
+// 		opts.MaxWidth = 80
+// 		opts.Color = true
+// 	})
+func PrettyWith(v interface{}, fns ...func(opts *PrettyOptions)) (string, error) {
+	bs, err := Encode(v)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := PrettyBytes(bs, fns...)
+	return string(out), err
+}
+
+// PrettyBytes format the JSON bytes. see PrettyWith()
+func PrettyBytes(json []byte, fns ...func(opts *PrettyOptions)) ([]byte, error) {
+	out, err := jsonpretty.Format(json, newPrettyOptions(fns))
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: format invalid JSON: %w", err)
+	}
+	return out, nil
+}
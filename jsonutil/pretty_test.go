@@ -0,0 +1,125 @@
+package jsonutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/color"
+	"github.com/urionz/goutil/jsonutil"
+)
+
+func TestPrettyWith(t *testing.T) {
+	is := assert.New(t)
+
+	type user struct {
+		Name string   `json:"name"`
+		Age  int      `json:"age"`
+		Tags []string `json:"tags"`
+	}
+
+	// keep the struct fields order
+	str, err := jsonutil.PrettyWith(user{"tom", 20, []string{"a", "b"}}, func(opts *jsonutil.PrettyOptions) {
+		opts.Indent = "  "
+	})
+	is.NoError(err)
+	is.Equal(`{
+  "name": "tom",
+  "age": 20,
+  "tags": [
+    "a",
+    "b"
+  ]
+}`, str)
+
+	str, err = jsonutil.PrettyWith(map[string]interface{}{"a": []int{}, "b": map[string]int{}, "c": nil})
+	is.NoError(err)
+	is.Equal("{\n    \"a\": [],\n    \"b\": {},\n    \"c\": null\n}", str)
+
+	_, err = jsonutil.PrettyWith(make(chan int))
+	is.Error(err)
+}
+
+func TestPrettyBytes(t *testing.T) {
+	is := assert.New(t)
+
+	src := `{"b": 1, "a": {"z": [1, 2, [3, 4]], "y": [{"k": true}]}, "c": ["<tag>", 1.50, "long text"]}`
+
+	bs, err := jsonutil.PrettyBytes([]byte(src), func(opts *jsonutil.PrettyOptions) {
+		opts.Indent = "  "
+		opts.SortKeys = true
+		opts.MaxWidth = 23
+	})
+	is.NoError(err)
+	is.Equal(`{
+  "a": {
+    "y": [
+      {
+        "k": true
+      }
+    ],
+    "z": [1, 2, [3, 4]]
+  },
+  "b": 1,
+  "c": [
+    "<tag>",
+    1.50,
+    "long text"
+  ]
+}`, string(bs))
+
+	// the array out of width
+	bs, err = jsonutil.PrettyBytes([]byte(`{"list": [1, 2, 3]}`), func(opts *jsonutil.PrettyOptions) {
+		opts.MaxWidth = 20
+	})
+	is.NoError(err)
+	is.Equal("{\n    \"list\": [\n        1,\n        2,\n        3\n    ]\n}", string(bs))
+
+	bs, err = jsonutil.PrettyBytes([]byte(`{"list": [1, 2, 3]}`), func(opts *jsonutil.PrettyOptions) {
+		opts.MaxWidth = 21
+	})
+	is.NoError(err)
+	is.Equal("{\n    \"list\": [1, 2, 3]\n}", string(bs))
+
+	bs, err = jsonutil.PrettyBytes([]byte(` "str" `))
+	is.NoError(err)
+	is.Equal(`"str"`, string(bs))
+
+	bs, err = jsonutil.PrettyBytes([]byte("123 \n"))
+	is.NoError(err)
+	is.Equal(`123`, string(bs))
+
+	for _, invalid := range []string{`{"a":}`, `[1, 2`, `{} {}`, ``, `{"a":1} x`, `1 2`, `[] ]`} {
+		_, err = jsonutil.PrettyBytes([]byte(invalid))
+		is.Error(err, invalid)
+	}
+}
+
+func TestPrettyBytes_color(t *testing.T) {
+	is := assert.New(t)
+
+	bs, err := jsonutil.PrettyBytes([]byte(`{"a": ["s", 1, true, null]}`), func(opts *jsonutil.PrettyOptions) {
+		opts.Color = true
+		opts.MaxWidth = 80
+	})
+	is.NoError(err)
+
+	th := jsonutil.DefaultTheme
+	want := "{\n    " + render(th.Key, `"a"`) + ": [" + render(th.String, `"s"`) + ", " +
+		render(th.Number, "1") + ", " + render(th.Bool, "true") + ", " + render(th.Null, "null") + "]\n}"
+	is.Equal(want, string(bs))
+	is.Equal(`{
+    "a": ["s", 1, true, null]
+}`, color.ClearCode(string(bs)))
+
+	// custom theme
+	bs, err = jsonutil.PrettyBytes([]byte(`{"a": 1}`), func(opts *jsonutil.PrettyOptions) {
+		opts.Color = true
+		opts.Theme = &jsonutil.ColorTheme{Number: color.FgRed.Code()}
+	})
+	is.NoError(err)
+	is.Equal("{\n    \"a\": "+render(color.FgRed.Code(), "1")+"\n}", string(bs))
+}
+
+func render(code, str string) string {
+	return "\x1b[" + code + "m" + str + "\x1b[0m"
+}
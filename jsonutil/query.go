@@ -0,0 +1,474 @@
+package jsonutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/json-iterator/go"
+	"github.com/urionz/goutil"
+)
+
+// ErrNotFound the path not found in the JSON
+var ErrNotFound = errors.New("jsonutil: path not found")
+
+// kinds of the path segment
+const (
+	segKey = iota
+	segIndex
+	segWildcard
+	segFilter
+)
+
+// pathSeg one segment of the query path
+type pathSeg struct {
+	kind  int
+	key   string
+	index int
+	// for filter segment
+	filter *pathFilter
+}
+
+// pathFilter the filter expression. eg: "@.age > 18", "@.email"
+type pathFilter struct {
+	field []pathSeg
+	op    string
+	// the literal value to compare, decoded from JSON
+	value interface{}
+}
+
+// Get value by path from the raw JSON, without decoding the whole document.
+// returns empty value on the path not found or invalid.
+// if the path has wildcards or filters, the value is []interface{} of all the matched values.
+//
+// Path syntax:
+// 	users[0].profile.name  - object keys and array indices
+// 	users[-1]              - negative index from the end
+// 	users[*].name, users.* - wildcards
+// 	users[?(@.age >= 18)]  - filters, ops: == != > >= < <=
+// 	users[?(@.email)]      - filter by field exists
+// 	["key.with.dots"]      - quoted key
+//
+// Usage:
+// 	name := jsonutil.Get(jsonBytes, "users[0].profile.name").String()
+func Get(json []byte, path string) goutil.Value {
+	segs, err := parsePath(path)
+	if err != nil {
+		return goutil.Value{}
+	}
+
+	vs, err := query(json, segs, !isMultiPath(segs))
+	if err != nil || len(vs) == 0 {
+		return goutil.Value{}
+	}
+
+	if isMultiPath(segs) {
+		return goutil.Value{V: vs}
+	}
+	return goutil.Value{V: vs[0]}
+}
+
+// Query all the matched values by path from the raw JSON.
+// returns ErrNotFound on no value matched. see Get() for the path syntax.
+func Query(json []byte, path string) ([]interface{}, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	vs, err := query(json, segs, false)
+	if err == nil && len(vs) == 0 {
+		err = ErrNotFound
+	}
+	return vs, err
+}
+
+// check the path can match multi values
+func isMultiPath(segs []pathSeg) bool {
+	for _, seg := range segs {
+		if seg.kind == segWildcard || seg.kind == segFilter {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// ------------------ path parser ------------------
+//
+
+func parsePath(path string) ([]pathSeg, error) {
+	p := &pathParser{src: path}
+	segs, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: invalid path %q: %w", path, err)
+	}
+	return segs, nil
+}
+
+type pathParser struct {
+	src string
+	pos int
+}
+
+func (p *pathParser) parse() (segs []pathSeg, err error) {
+	// the root flag "$" is optional
+	if strings.HasPrefix(p.src, "$") {
+		p.pos++
+	}
+
+	for p.pos < len(p.src) {
+		var seg pathSeg
+		switch c := p.src[p.pos]; c {
+		case '.':
+			p.pos++
+			seg, err = p.parseKey()
+		case '[':
+			p.pos++
+			seg, err = p.parseBracket()
+		default:
+			if len(segs) > 0 {
+				return nil, fmt.Errorf("unexpected char %q at %d", c, p.pos)
+			}
+			seg, err = p.parseKey()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// parse key after ".". eg: "name", "*"
+func (p *pathParser) parseKey() (pathSeg, error) {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != '.' && p.src[p.pos] != '[' {
+		p.pos++
+	}
+
+	key := p.src[start:p.pos]
+	if key == "" {
+		return pathSeg{}, fmt.Errorf("empty key at %d", start)
+	}
+	if key == "*" {
+		return pathSeg{kind: segWildcard}, nil
+	}
+	return pathSeg{kind: segKey, key: key}, nil
+}
+
+// parse the content in "[]". eg: "[0]", "[*]", "['key']", "[?(@.age > 18)]"
+func (p *pathParser) parseBracket() (pathSeg, error) {
+	end := p.closeBracket()
+	if end < 0 {
+		return pathSeg{}, fmt.Errorf("missing ']' for '[' at %d", p.pos-1)
+	}
+
+	inner := strings.TrimSpace(p.src[p.pos:end])
+	p.pos = end + 1
+
+	switch {
+	case inner == "*":
+		return pathSeg{kind: segWildcard}, nil
+	case inner == "":
+		return pathSeg{}, fmt.Errorf("empty brackets at %d", end-1)
+	case inner[0] == '\'' || inner[0] == '"':
+		key, err := unquote(inner)
+		return pathSeg{kind: segKey, key: key}, err
+	case inner[0] == '?':
+		filter, err := parseFilter(inner[1:])
+		return pathSeg{kind: segFilter, filter: filter}, err
+	}
+
+	idx, err := strconv.Atoi(inner)
+	if err != nil {
+		return pathSeg{}, fmt.Errorf("invalid index %q", inner)
+	}
+	return pathSeg{kind: segIndex, index: idx}, nil
+}
+
+// find the position of the close bracket, skip the quoted string and the nested brackets
+func (p *pathParser) closeBracket() int {
+	var quote byte
+	var depth int
+	for i := p.pos; i < len(p.src); i++ {
+		c := p.src[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+var filterOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// parse filter expression. eg: "(@.age > 18)", "@.name == 'tom'", "(@.email)"
+func parseFilter(expr string) (*pathFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+
+	if !strings.HasPrefix(expr, "@") {
+		return nil, fmt.Errorf("filter must start with '@', but got %q", expr)
+	}
+
+	filter := &pathFilter{}
+	field := expr[1:]
+	if pos, op := indexFilterOp(expr); pos > 0 {
+		filter.op = op
+		field = strings.TrimSpace(expr[1:pos])
+
+		lit := strings.TrimSpace(expr[pos+len(op):])
+		if strings.HasPrefix(lit, "'") {
+			str, err := unquote(lit)
+			if err != nil {
+				return nil, err
+			}
+			filter.value = str
+		} else if err := Decode([]byte(lit), &filter.value); err != nil {
+			return nil, fmt.Errorf("invalid filter value %q", lit)
+		}
+	}
+
+	// "@" is the element self
+	if field != "" {
+		p := &pathParser{src: field}
+		segs, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		filter.field = segs
+	}
+	return filter, nil
+}
+
+// find the first operator outside the quoted strings, returns -1 on not found.
+func indexFilterOp(expr string) (int, string) {
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		default:
+			for _, op := range filterOps {
+				if strings.HasPrefix(expr[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// unquote the single or double quoted string
+func unquote(str string) (string, error) {
+	if len(str) < 2 || str[0] != str[len(str)-1] {
+		return "", fmt.Errorf("invalid quoted string %s", str)
+	}
+
+	if str[0] == '\'' {
+		str = `"` + strings.ReplaceAll(str[1:len(str)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(str)
+}
+
+//
+// ------------------ query on raw JSON ------------------
+//
+
+type querier struct {
+	// stop on first value matched
+	first bool
+	done  bool
+	vs    []interface{}
+}
+
+func query(json []byte, segs []pathSeg, first bool) ([]interface{}, error) {
+	iter := parser.BorrowIterator(json)
+	defer parser.ReturnIterator(iter)
+
+	q := &querier{first: first}
+	q.walk(iter, segs)
+
+	if iter.Error != nil && iter.Error != io.EOF && !q.done {
+		return nil, fmt.Errorf("jsonutil: query invalid JSON: %w", iter.Error)
+	}
+	return q.vs, nil
+}
+
+// walk the current value of the iterator by the path segments
+func (q *querier) walk(iter *jsoniter.Iterator, segs []pathSeg) {
+	if len(segs) == 0 {
+		q.vs = append(q.vs, iter.Read())
+		q.done = q.first
+		return
+	}
+
+	seg, rest := segs[0], segs[1:]
+	next := iter.WhatIsNext()
+
+	switch seg.kind {
+	case segKey:
+		if next != jsoniter.ObjectValue {
+			iter.Skip()
+			return
+		}
+
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			if key == seg.key {
+				q.walk(iter, rest)
+			} else {
+				iter.Skip()
+			}
+			return !q.done
+		})
+	case segIndex:
+		if next != jsoniter.ArrayValue {
+			iter.Skip()
+			return
+		}
+
+		if seg.index < 0 {
+			q.walkNegIndex(iter, seg.index, rest)
+			return
+		}
+
+		i := 0
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			if i == seg.index {
+				q.walk(iter, rest)
+			} else {
+				iter.Skip()
+			}
+			i++
+			return !q.done
+		})
+	case segWildcard, segFilter:
+		each := func(iter *jsoniter.Iterator) bool {
+			if seg.kind == segWildcard {
+				q.walk(iter, rest)
+			} else {
+				q.walkFilter(iter, seg.filter, rest)
+			}
+			return !q.done
+		}
+
+		switch next {
+		case jsoniter.ObjectValue:
+			iter.ReadMapCB(func(iter *jsoniter.Iterator, _ string) bool {
+				return each(iter)
+			})
+		case jsoniter.ArrayValue:
+			iter.ReadArrayCB(each)
+		default:
+			iter.Skip()
+		}
+	}
+}
+
+// the negative index need the array length, so collect the raw elements first.
+func (q *querier) walkNegIndex(iter *jsoniter.Iterator, index int, rest []pathSeg) {
+	var raws [][]byte
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		raws = append(raws, iter.SkipAndReturnBytes())
+		return true
+	})
+
+	if index += len(raws); index >= 0 {
+		q.walkRaw(raws[index], rest)
+	}
+}
+
+// walk the element on matched the filter
+func (q *querier) walkFilter(iter *jsoniter.Iterator, filter *pathFilter, rest []pathSeg) {
+	raw := iter.SkipAndReturnBytes()
+	vs, err := query(raw, filter.field, true)
+	if err != nil {
+		return
+	}
+
+	if len(vs) > 0 && filter.match(vs[0]) {
+		q.walkRaw(raw, rest)
+	}
+}
+
+func (q *querier) walkRaw(raw []byte, segs []pathSeg) {
+	iter := parser.BorrowIterator(raw)
+	defer parser.ReturnIterator(iter)
+
+	q.walk(iter, segs)
+}
+
+// check the field value is match the filter
+func (f *pathFilter) match(v interface{}) bool {
+	// only check the field exists
+	if f.op == "" {
+		return true
+	}
+
+	switch want := f.value.(type) {
+	case float64:
+		if got, ok := v.(float64); ok {
+			return compare(f.op, got-want)
+		}
+	case string:
+		if got, ok := v.(string); ok {
+			return compare(f.op, float64(strings.Compare(got, want)))
+		}
+	default: // bool, null
+		if f.op == "==" {
+			return v == want
+		}
+		if f.op == "!=" {
+			return v != want
+		}
+		return false
+	}
+
+	// the type is different
+	return f.op == "!="
+}
+
+// compare by the op and the diff of two values
+func compare(op string, diff float64) bool {
+	switch op {
+	case "==":
+		return diff == 0
+	case "!=":
+		return diff != 0
+	case ">":
+		return diff > 0
+	case ">=":
+		return diff >= 0
+	case "<":
+		return diff < 0
+	case "<=":
+		return diff <= 0
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package jsonutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/jsonutil"
+)
+
+var queryJSON = []byte(`{
+	"name": "app",
+	"version": 1.2,
+	"key.with.dots": true,
+	"users": [
+		{"name": "inhere", "age": 22, "email": "in@example.com", "profile": {"city": "chengdu"}},
+		{"name": "tom", "age": 17, "profile": {"city": "beijing"}},
+		{"name": "john", "age": 30, "profile": {"city": "shanghai"}, "tags": ["a", "b"]}
+	],
+	"empty": null
+}`)
+
+func TestGet(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("app", jsonutil.Get(queryJSON, "name").String())
+	is.Equal("app", jsonutil.Get(queryJSON, "$.name").String())
+	is.Equal(1.2, jsonutil.Get(queryJSON, "version").Float64())
+	is.True(jsonutil.Get(queryJSON, `["key.with.dots"]`).Bool())
+	is.True(jsonutil.Get(queryJSON, `$['key.with.dots']`).Bool())
+
+	is.Equal("inhere", jsonutil.Get(queryJSON, "users[0].name").String())
+	is.Equal(22, jsonutil.Get(queryJSON, "users[0].age").Int())
+	is.Equal("chengdu", jsonutil.Get(queryJSON, "users[0].profile.city").String())
+	is.Equal("john", jsonutil.Get(queryJSON, "users[-1].name").String())
+	is.Equal("b", jsonutil.Get(queryJSON, "users[2].tags[1]").String())
+	is.Equal(map[string]interface{}{"city": "beijing"}, jsonutil.Get(queryJSON, "users[1].profile").Val())
+
+	// not found
+	is.True(jsonutil.Get(queryJSON, "users[3].name").IsEmpty())
+	is.True(jsonutil.Get(queryJSON, "users[-4].name").IsEmpty())
+	is.True(jsonutil.Get(queryJSON, "name.sub").IsEmpty())
+	is.True(jsonutil.Get(queryJSON, "empty").IsEmpty())
+	is.True(jsonutil.Get(queryJSON, "not-exists").IsEmpty())
+	// invalid
+	is.True(jsonutil.Get(queryJSON, "users[abc]").IsEmpty())
+	is.True(jsonutil.Get([]byte(`{"name": `), "name").IsEmpty())
+}
+
+func TestGet_multi(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal([]interface{}{"inhere", "tom", "john"}, jsonutil.Get(queryJSON, "users[*].name").Val())
+	is.Equal([]interface{}{"chengdu", "beijing", "shanghai"}, jsonutil.Get(queryJSON, "users.*.profile.city").Val())
+	is.Equal([]interface{}{"inhere", "john"}, jsonutil.Get(queryJSON, "users[?(@.age >= 18)].name").Val())
+	is.Equal([]interface{}{"tom"}, jsonutil.Get(queryJSON, "users[?(@.age<18)].name").Val())
+	is.Equal([]interface{}{22.0}, jsonutil.Get(queryJSON, `users[?(@.name == "inhere")].age`).Val())
+	is.Equal([]interface{}{"beijing"}, jsonutil.Get(queryJSON, `users[?@.profile.city=='beijing'].profile.city`).Val())
+	is.Equal([]interface{}{"in@example.com"}, jsonutil.Get(queryJSON, "users[?(@.email)].email").Val())
+	is.Equal([]interface{}{"tom", "john"}, jsonutil.Get(queryJSON, `users[?(@.name != "inhere")].name`).Val())
+	is.Equal([]interface{}{2.0, 2.0}, jsonutil.Get([]byte(`[1, 2, 3, 2]`), "[?(@ == 2)]").Val())
+
+	is.True(jsonutil.Get(queryJSON, "users[?(@.age > 100)].name").IsEmpty())
+
+	// the operators in the quoted strings
+	opsJSON := []byte(`[{"a<b": 1, "op": ">="}, {"a<b": 2, "op": "=="}]`)
+	is.Equal([]interface{}{">="}, jsonutil.Get(opsJSON, `[?(@.op == ">=")].op`).Val())
+	is.Equal([]interface{}{"=="}, jsonutil.Get(opsJSON, `[?(@.op != '>=')].op`).Val())
+	is.Equal([]interface{}{2.0}, jsonutil.Get(opsJSON, `[?(@['a<b'] > 1)]['a<b']`).Val())
+}
+
+func TestQuery(t *testing.T) {
+	is := assert.New(t)
+
+	vs, err := jsonutil.Query(queryJSON, "users[1].name")
+	is.NoError(err)
+	is.Equal([]interface{}{"tom"}, vs)
+
+	_, err = jsonutil.Query(queryJSON, "users[5]")
+	is.Equal(jsonutil.ErrNotFound, err)
+
+	for _, path := range []string{"users[", "users..name", "users[?(age > 1)]", "users[?(@.age > x)]", "users[0]name"} {
+		_, err = jsonutil.Query(queryJSON, path)
+		is.Error(err, path)
+	}
+
+	_, err = jsonutil.Query([]byte(`{"users": [1, }`), "users[*]")
+	is.Error(err)
+}
@@ -0,0 +1,734 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SchemaDraft the JSON Schema dialect of the generated schema
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaTypes the "type" keyword of schema, can be a string or an array of strings.
+type SchemaTypes []string
+
+// MarshalJSON encode as string on only one type.
+func (ts SchemaTypes) MarshalJSON() ([]byte, error) {
+	if len(ts) == 1 {
+		return Encode(ts[0])
+	}
+	return Encode([]string(ts))
+}
+
+// UnmarshalJSON decode from string or array of strings.
+func (ts *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := Decode(data, &one); err == nil {
+		*ts = SchemaTypes{one}
+		return nil
+	}
+	return Decode(data, (*[]string)(ts))
+}
+
+// Schema a JSON Schema, support a subset of the draft 2020-12.
+//
+// Supported keywords:
+// 	type, enum, const, $ref, $defs, definitions
+// 	properties, required, additionalProperties, minProperties, maxProperties
+// 	items, minItems, maxItems, uniqueItems
+// 	pattern, minLength, maxLength, format(only for annotation)
+// 	minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+// 	allOf, anyOf, oneOf, not
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type    SchemaTypes   `json:"type,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty"`
+	Const   interface{}   `json:"const,omitempty"`
+	Default interface{}   `json:"default,omitempty"`
+	Format  string        `json:"format,omitempty"`
+
+	// for object
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+
+	// for array
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	// for string
+	Pattern   string `json:"pattern,omitempty"`
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+
+	// for number
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	// combinations
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	// the boolean schema. true: always valid, false: always invalid
+	boolean *bool
+	// the const keyword is present, the Const can be nil for {"const": null}
+	hasConst bool
+	// the root schema, for resolve the $ref
+	root    *Schema
+	pattern *regexp.Regexp
+	// for the root schema. the raw schema document and the resolved refs
+	raw  interface{}
+	refs map[string]*Schema
+}
+
+// schemaAlias for avoid the recursion of UnmarshalJSON
+type schemaAlias Schema
+
+// UnmarshalJSON decode the schema, support the boolean schema.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var bl bool
+	if err := Decode(data, &bl); err == nil {
+		*s = Schema{boolean: &bl}
+		return nil
+	}
+
+	if err := Decode(data, (*schemaAlias)(s)); err != nil {
+		return err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := Decode(data, &keys); err != nil {
+		return err
+	}
+	_, s.hasConst = keys["const"]
+	return nil
+}
+
+// MarshalJSON encode the schema, support the boolean schema.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return Encode(*s.boolean)
+	}
+
+	bs, err := Encode((*schemaAlias)(s))
+	if err != nil || !s.hasConst || s.Const != nil {
+		return bs, err
+	}
+
+	// the const null is omitted by the omitempty
+	if len(bs) == 2 { // "{}"
+		return []byte(`{"const":null}`), nil
+	}
+	return append(bs[:len(bs)-1], `,"const":null}`...), nil
+}
+
+// ParseSchema parse and compile the JSON Schema document.
+// the $ref can only refer to the schema in the same document. eg: "#/$defs/user"
+func ParseSchema(data []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := Decode(data, s); err != nil {
+		return nil, fmt.Errorf("jsonutil: decode schema: %w", err)
+	}
+
+	// the raw document for resolve the $ref by JSON pointer
+	if err := Decode(data, &s.raw); err != nil {
+		return nil, fmt.Errorf("jsonutil: decode schema: %w", err)
+	}
+
+	s.refs = map[string]*Schema{"#": s}
+	if err := s.compile(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MustParseSchema parse the JSON Schema, will panic on error
+func MustParseSchema(data []byte) *Schema {
+	s, err := ParseSchema(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// compile the patterns and resolve the refs of the schema and the sub schemas.
+func (s *Schema) compile(root *Schema) (err error) {
+	if s == nil || s.root != nil {
+		return nil
+	}
+
+	s.root = root
+	if s.Pattern != "" {
+		if s.pattern, err = regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("jsonutil: invalid schema pattern %q: %w", s.Pattern, err)
+		}
+	}
+
+	if s.Ref != "" {
+		if _, err := root.resolveRef(s.Ref); err != nil {
+			return err
+		}
+	}
+
+	subs := []*Schema{s.AdditionalProperties, s.Items, s.Not}
+	subs = append(subs, s.AllOf...)
+	subs = append(subs, s.AnyOf...)
+	subs = append(subs, s.OneOf...)
+	for _, mp := range []map[string]*Schema{s.Properties, s.Defs, s.Definitions} {
+		for _, sub := range mp {
+			subs = append(subs, sub)
+		}
+	}
+
+	for _, sub := range subs {
+		if err := sub.compile(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve the $ref in the root schema document. eg: "#", "#/$defs/user"
+func (s *Schema) resolveRef(ref string) (*Schema, error) {
+	if sub, ok := s.refs[ref]; ok {
+		return sub, nil
+	}
+
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("jsonutil: unsupported schema $ref %q, only support the ref in document", ref)
+	}
+
+	tokens, _ := parsePointer(ref[1:])
+	val, err := getPointer(s.raw, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: resolve schema $ref %q: %w", ref, err)
+	}
+
+	bs, err := Encode(val)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Schema{}
+	if err := Decode(bs, sub); err != nil {
+		return nil, fmt.Errorf("jsonutil: resolve schema $ref %q: %w", ref, err)
+	}
+
+	// add to cache before compile, for the recursive refs
+	s.refs[ref] = sub
+	return sub, sub.compile(s)
+}
+
+// ValidationError the error of a value does not match the schema
+type ValidationError struct {
+	// Path the JSON pointer of the invalid value. eg: "/users/0/age"
+	Path string
+	// Keyword the schema keyword. eg: "minimum"
+	Keyword string
+	Message string
+}
+
+// Error message. eg: `/users/0/age: minimum: must be >= 18`
+func (e *ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + e.Keyword + ": " + e.Message
+}
+
+// ValidationErrors all the validation errors
+type ValidationErrors []*ValidationError
+
+// Error message, one error per line.
+func (es ValidationErrors) Error() string {
+	ss := make([]string, len(es))
+	for i, e := range es {
+		ss[i] = e.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+// ValidateSchema validate the JSON document by the JSON Schema document.
+// returns ValidationErrors on the document is invalid.
+func ValidateSchema(schema, doc []byte) error {
+	s, err := ParseSchema(schema)
+	if err != nil {
+		return err
+	}
+	return s.Validate(doc)
+}
+
+// Validate the JSON document, returns ValidationErrors on the document is invalid.
+func (s *Schema) Validate(doc []byte) error {
+	v, err := decodeDoc(doc)
+	if err != nil {
+		return fmt.Errorf("jsonutil: decode document: %w", err)
+	}
+	return s.validateDoc(v)
+}
+
+// ValidateValue validate the Go value, it will be encoded as JSON for validate.
+// returns ValidationErrors on the value is invalid.
+func (s *Schema) ValidateValue(v interface{}) error {
+	bs, err := Encode(v)
+	if err != nil {
+		return err
+	}
+	return s.Validate(bs)
+}
+
+func (s *Schema) validateDoc(v interface{}) error {
+	if s.root == nil {
+		return fmt.Errorf("jsonutil: the schema is not compiled, please create by ParseSchema()")
+	}
+
+	var errs ValidationErrors
+	s.validate(v, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *ValidationErrors) {
+	addErr := func(keyword, format string, args ...interface{}) {
+		*errs = append(*errs, &ValidationError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.boolean != nil {
+		if !*s.boolean {
+			addErr("false", "the value is not allowed")
+		}
+		return
+	}
+
+	if s.Ref != "" {
+		ref, err := s.root.resolveRef(s.Ref)
+		if err != nil {
+			addErr("$ref", err.Error())
+			return
+		}
+		ref.validate(v, path, errs)
+	}
+
+	if len(s.Type) > 0 && !s.Type.match(v) {
+		addErr("type", "must be %s, but got %s", strings.Join(s.Type, " or "), jsonTypeOf(v))
+		return
+	}
+
+	if len(s.Enum) > 0 && !containsJSON(s.Enum, v) {
+		addErr("enum", "must be one of %s", encodeString(s.Enum))
+	}
+
+	if (s.Const != nil || s.hasConst) && !jsonEqual(s.Const, v) {
+		addErr("const", "must be equal to %s", encodeString(s.Const))
+	}
+
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(tv, path, errs, addErr)
+	case []interface{}:
+		s.validateArray(tv, path, errs, addErr)
+	case string:
+		s.validateString(tv, addErr)
+	case json.Number:
+		s.validateNumber(tv, addErr)
+	}
+
+	s.validateCombinations(v, path, errs, addErr)
+}
+
+type addErrFunc func(keyword, format string, args ...interface{})
+
+func (s *Schema) validateObject(mp map[string]interface{}, path string, errs *ValidationErrors, addErr addErrFunc) {
+	for _, name := range s.Required {
+		if _, ok := mp[name]; !ok {
+			addErr("required", "missing property %q", name)
+		}
+	}
+
+	if s.MinProperties != nil && len(mp) < *s.MinProperties {
+		addErr("minProperties", "must have at least %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(mp) > *s.MaxProperties {
+		addErr("maxProperties", "must have at most %d properties", *s.MaxProperties)
+	}
+
+	for _, key := range sortedKeys(mp) {
+		sub := path + "/" + escapePointer(key)
+		if ps, ok := s.Properties[key]; ok {
+			ps.validate(mp[key], sub, errs)
+		} else if s.AdditionalProperties != nil {
+			if ap := s.AdditionalProperties; ap.boolean != nil && !*ap.boolean {
+				addErr("additionalProperties", "property %q is not allowed", key)
+			} else {
+				ap.validate(mp[key], sub, errs)
+			}
+		}
+	}
+}
+
+func (s *Schema) validateArray(ls []interface{}, path string, errs *ValidationErrors, addErr addErrFunc) {
+	if s.MinItems != nil && len(ls) < *s.MinItems {
+		addErr("minItems", "must have at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(ls) > *s.MaxItems {
+		addErr("maxItems", "must have at most %d items", *s.MaxItems)
+	}
+
+	if s.UniqueItems {
+		for i := 1; i < len(ls); i++ {
+			if containsJSON(ls[:i], ls[i]) {
+				addErr("uniqueItems", "the item #%d is duplicated", i)
+				break
+			}
+		}
+	}
+
+	if s.Items != nil {
+		for i, item := range ls {
+			s.Items.validate(item, path+"/"+strconv.Itoa(i), errs)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, addErr addErrFunc) {
+	n := utf8.RuneCountInString(str)
+	if s.MinLength != nil && n < *s.MinLength {
+		addErr("minLength", "length must be >= %d", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		addErr("maxLength", "length must be <= %d", *s.MaxLength)
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		addErr("pattern", "must match the pattern %q", s.Pattern)
+	}
+}
+
+func (s *Schema) validateNumber(num json.Number, addErr addErrFunc) {
+	f, _ := num.Float64()
+	if s.Minimum != nil && f < *s.Minimum {
+		addErr("minimum", "must be >= %v", *s.Minimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		addErr("maximum", "must be <= %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum {
+		addErr("exclusiveMinimum", "must be > %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+		addErr("exclusiveMaximum", "must be < %v", *s.ExclusiveMaximum)
+	}
+
+	if s.MultipleOf != nil && *s.MultipleOf > 0 && !isMultipleOf(num, *s.MultipleOf) {
+		addErr("multipleOf", "must be multiple of %v", *s.MultipleOf)
+	}
+}
+
+// check the number is multiple of the m. compare by the decimal values, for avoid the float precision loss.
+// eg: 0.3 is multiple of 0.1
+func isMultipleOf(num json.Number, m float64) bool {
+	x, ok1 := new(big.Rat).SetString(num.String())
+	y, ok2 := new(big.Rat).SetString(strconv.FormatFloat(m, 'g', -1, 64))
+	if !ok1 || !ok2 {
+		f, _ := num.Float64()
+		q := f / m
+		return q == math.Trunc(q)
+	}
+	return x.Quo(x, y).IsInt()
+}
+
+func (s *Schema) validateCombinations(v interface{}, path string, errs *ValidationErrors, addErr addErrFunc) {
+	for _, sub := range s.AllOf {
+		sub.validate(v, path, errs)
+	}
+
+	if len(s.AnyOf) > 0 && countMatched(s.AnyOf, v, path) == 0 {
+		addErr("anyOf", "must match at least one schema")
+	}
+
+	if len(s.OneOf) > 0 {
+		if n := countMatched(s.OneOf, v, path); n != 1 {
+			addErr("oneOf", "must match exactly one schema, but matched %d", n)
+		}
+	}
+
+	if s.Not != nil && countMatched([]*Schema{s.Not}, v, path) == 1 {
+		addErr("not", "must not match the schema")
+	}
+}
+
+// count the schemas matched the value
+func countMatched(ss []*Schema, v interface{}, path string) (n int) {
+	for _, sub := range ss {
+		var errs ValidationErrors
+		if sub.validate(v, path, &errs); len(errs) == 0 {
+			n++
+		}
+	}
+	return
+}
+
+// check the value match one of the types
+func (ts SchemaTypes) match(v interface{}) bool {
+	typ := jsonTypeOf(v)
+	for _, t := range ts {
+		if t == typ || (t == "number" && typ == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// get the JSON type name of the decoded value
+func jsonTypeOf(v interface{}) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if f, err := tv.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func containsJSON(ls []interface{}, v interface{}) bool {
+	for _, item := range ls {
+		if jsonEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeString(v interface{}) string {
+	bs, _ := Encode(v)
+	return string(bs)
+}
+
+//
+// ------------------ generate schema from struct ------------------
+//
+
+// GenerateSchema generate the JSON Schema from the Go value, the struct fields are named by the json tag.
+// the fields without "omitempty" option are required, and allow null on the type is pointer, slice or map.
+// the recursive struct types will be defined in the "$defs".
+// the returned schema is compiled, can be used for validate directly.
+//
+// Usage:
+// 	s := jsonutil.GenerateSchema(&Config{})
+// 	bs, err := jsonutil.Encode(s)
+func GenerateSchema(v interface{}) *Schema {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	g := &schemaGen{root: rt, stack: make(map[reflect.Type]bool), recursive: make(map[reflect.Type]bool)}
+	s := g.typeSchema(rt)
+	s.Schema = SchemaDraft
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+
+	// compile for the validation. the generated refs are always can be resolved.
+	bs, _ := Encode(s)
+	_ = Decode(bs, &s.raw)
+	s.refs = map[string]*Schema{"#": s}
+	_ = s.compile(s)
+	return s
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+type schemaGen struct {
+	root reflect.Type
+	defs map[string]*Schema
+	// the struct types on the current path
+	stack map[reflect.Type]bool
+	// the struct types referenced recursively
+	recursive map[reflect.Type]bool
+}
+
+func (g *schemaGen) typeSchema(rt reflect.Type) *Schema {
+	// interface{}, allow any value
+	if rt == nil {
+		return &Schema{}
+	}
+
+	switch rt {
+	case timeType:
+		return &Schema{Type: SchemaTypes{"string"}, Format: "date-time"}
+	case bytesType:
+		return &Schema{Type: SchemaTypes{"string"}, Format: "byte"}
+	}
+
+	switch rt.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(rt.Elem())
+	case reflect.Bool:
+		return &Schema{Type: SchemaTypes{"boolean"}}
+	case reflect.String:
+		return &Schema{Type: SchemaTypes{"string"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: SchemaTypes{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: SchemaTypes{"integer"}, Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaTypes{"number"}}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: SchemaTypes{"array"}, Items: g.elemSchema(rt.Elem())}
+	case reflect.Map:
+		return &Schema{Type: SchemaTypes{"object"}, AdditionalProperties: g.elemSchema(rt.Elem())}
+	case reflect.Struct:
+		return g.structSchema(rt)
+	}
+	return &Schema{}
+}
+
+// the schema of the slice or map element, the nil element is encoded as null.
+func (g *schemaGen) elemSchema(rt reflect.Type) *Schema {
+	s := g.typeSchema(rt)
+	if isNillable(rt) {
+		return nullable(s)
+	}
+	return s
+}
+
+// check the nil value of the type is encoded as null
+func isNillable(rt reflect.Type) bool {
+	kind := rt.Kind()
+	return kind == reflect.Ptr || kind == reflect.Slice || kind == reflect.Map
+}
+
+// allow the null value for the schema. the $ref schema will be wrapped by anyOf.
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: SchemaTypes{"null"}}}}
+	}
+
+	if len(s.Type) > 0 {
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
+
+func (g *schemaGen) structSchema(rt reflect.Type) *Schema {
+	if g.stack[rt] {
+		g.recursive[rt] = true
+		return &Schema{Ref: g.refName(rt)}
+	}
+
+	g.stack[rt] = true
+	defer delete(g.stack, rt)
+
+	s := &Schema{Type: SchemaTypes{"object"}, Properties: make(map[string]*Schema)}
+	g.addFields(s, rt)
+	sort.Strings(s.Required)
+
+	if !g.recursive[rt] || rt == g.root {
+		return s
+	}
+
+	if g.defs == nil {
+		g.defs = make(map[string]*Schema)
+	}
+	g.defs[rt.Name()] = s
+	return &Schema{Ref: g.refName(rt)}
+}
+
+func (g *schemaGen) refName(rt reflect.Type) string {
+	if rt == g.root {
+		return "#"
+	}
+	return "#/$defs/" + rt.Name()
+}
+
+// add the struct fields to the properties, the embedded struct fields will be inlined.
+func (g *schemaGen) addFields(s *Schema, rt reflect.Type) {
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if pos := strings.IndexByte(tag, ','); pos >= 0 {
+			name, opts = tag[:pos], tag[pos+1:]
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(s, ft)
+			continue
+		}
+
+		if sf.PkgPath != "" { // unexported
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		fs := g.typeSchema(sf.Type)
+		if strings.Contains(","+opts+",", ",string,") && fs.Ref == "" {
+			fs = &Schema{Type: SchemaTypes{"string"}}
+		}
+
+		// the nil value is encoded as null on without omitempty
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+			if isNillable(sf.Type) {
+				fs = nullable(fs)
+			}
+		}
+		s.Properties[name] = fs
+	}
+}
@@ -0,0 +1,273 @@
+package jsonutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/jsonutil"
+)
+
+var userSchema = []byte(`{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["name", "age"],
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "maxLength": 10, "pattern": "^[a-z]+$"},
+		"age": {"type": "integer", "minimum": 18, "exclusiveMaximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"score": {"type": ["number", "null"], "multipleOf": 0.5},
+		"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "uniqueItems": true},
+		"address": {"$ref": "#/$defs/address"},
+		"contact": {
+			"oneOf": [
+				{"type": "string", "pattern": "@"},
+				{"type": "object", "required": ["phone"]}
+			]
+		},
+		"friends": {"type": "array", "items": {"$ref": "#"}}
+	},
+	"additionalProperties": false,
+	"$defs": {
+		"address": {
+			"type": "object",
+			"properties": {
+				"city": {"type": "string"},
+				"zip": {"anyOf": [{"type": "string"}, {"type": "integer"}]}
+			},
+			"required": ["city"],
+			"not": {"required": ["country"]}
+		}
+	}
+}`)
+
+func TestSchema_Validate(t *testing.T) {
+	is := assert.New(t)
+
+	s, err := jsonutil.ParseSchema(userSchema)
+	is.NoError(err)
+
+	err = s.Validate([]byte(`{
+		"name": "inhere",
+		"age": 22,
+		"role": "admin",
+		"score": 9.5,
+		"tags": ["a", "b"],
+		"address": {"city": "chengdu", "zip": 610000},
+		"contact": {"phone": "123"},
+		"friends": [{"name": "tom", "age": 30, "score": null}]
+	}`))
+	is.NoError(err)
+
+	err = s.Validate([]byte(`{
+		"name": "Inhere123456",
+		"age": 17.5,
+		"role": "guest",
+		"score": 9.2,
+		"tags": ["a", "a"],
+		"address": {"zip": true, "country": "cn"},
+		"contact": 123,
+		"friends": [{"name": "tom"}],
+		"extra": 1
+	}`))
+	is.Error(err)
+
+	errs, ok := err.(jsonutil.ValidationErrors)
+	is.True(ok)
+
+	got := make(map[string]string)
+	for _, e := range errs {
+		got[e.Path+" "+e.Keyword] = e.Message
+	}
+
+	is.Contains(got, "/name maxLength")
+	is.Contains(got, "/name pattern")
+	is.Equal("must be integer, but got number", got["/age type"])
+	is.Contains(got, "/role enum")
+	is.Contains(got, "/score multipleOf")
+	is.Contains(got, "/tags uniqueItems")
+	is.Equal(`missing property "city"`, got["/address required"])
+	is.Contains(got, "/address/zip anyOf")
+	is.Contains(got, "/address not")
+	is.Equal("must match exactly one schema, but matched 0", got["/contact oneOf"])
+	is.Equal(`missing property "age"`, got["/friends/0 required"])
+	is.Equal(`property "extra" is not allowed`, got[" additionalProperties"])
+	is.Len(errs, 12)
+	is.Contains(err.Error(), `(root): additionalProperties: property "extra" is not allowed`)
+
+	// the root type
+	err = s.Validate([]byte(`[]`))
+	is.Equal("(root): type: must be object, but got array", err.Error())
+
+	// invalid document
+	is.Error(s.Validate([]byte(`{`)))
+}
+
+func TestParseSchema_error(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := jsonutil.ParseSchema([]byte(`{"type": `))
+	is.Error(err)
+
+	_, err = jsonutil.ParseSchema([]byte(`{"pattern": "[a-"}`))
+	is.Error(err)
+
+	_, err = jsonutil.ParseSchema([]byte(`{"items": {"$ref": "#/$defs/not-exists"}}`))
+	is.Error(err)
+
+	_, err = jsonutil.ParseSchema([]byte(`{"$ref": "http://example.com/schema.json"}`))
+	is.Error(err)
+
+	is.Panics(func() {
+		jsonutil.MustParseSchema([]byte(`{`))
+	})
+}
+
+func TestValidateSchema(t *testing.T) {
+	is := assert.New(t)
+
+	is.NoError(jsonutil.ValidateSchema([]byte(`true`), []byte(`1`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`false`), []byte(`1`)))
+	is.NoError(jsonutil.ValidateSchema([]byte(`{"const": 1}`), []byte(`1.0`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"const": {"a": 1}}`), []byte(`{"a": 2}`)))
+	is.NoError(jsonutil.ValidateSchema([]byte(`{"const": null}`), []byte(`null`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"const": null}`), []byte(`0`)))
+	is.NoError(jsonutil.ValidateSchema([]byte(`{"const": 0}`), []byte(`0`)))
+
+	s, err := jsonutil.ParseSchema([]byte(`{"properties": {"a": {"const": null}, "b": {}}}`))
+	is.NoError(err)
+	bs, err := jsonutil.Encode(s)
+	is.NoError(err)
+	is.JSONEq(`{"properties": {"a": {"const": null}, "b": {}}}`, string(bs))
+	is.NoError(jsonutil.ValidateSchema([]byte(`{"allOf": [{"minimum": 1}, {"maximum": 3}]}`), []byte(`2`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"allOf": [{"minimum": 1}, {"maximum": 3}]}`), []byte(`4`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"minProperties": 2}`), []byte(`{"a": 1}`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"maxItems": 1}`), []byte(`[1, 2]`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"additionalProperties": {"type": "string"}}`), []byte(`{"a": 1}`)))
+	is.Error(jsonutil.ValidateSchema([]byte(`{"definitions": {"a": {"type": "string"}}, "$ref": "#/definitions/a"}`), []byte(`1`)))
+}
+
+type schemaAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type schemaMeta struct {
+	Created time.Time `json:"created"`
+}
+
+type schemaUser struct {
+	schemaMeta
+	Name     string            `json:"name"`
+	Age      uint8             `json:"age"`
+	Score    float64           `json:"score,omitempty"`
+	ID       int64             `json:"id,string"`
+	Tags     []string          `json:"tags,omitempty"`
+	Address  *schemaAddress    `json:"address,omitempty"`
+	Extra    map[string]int    `json:"extra,omitempty"`
+	Any      interface{}       `json:"any,omitempty"`
+	Raw      []byte            `json:"raw,omitempty"`
+	Friends  []*schemaUser     `json:"friends,omitempty"`
+	Labels   map[string]string `json:"-"`
+	internal string
+	Enabled  bool
+}
+
+func TestGenerateSchema(t *testing.T) {
+	is := assert.New(t)
+
+	s := jsonutil.GenerateSchema(&schemaUser{})
+	bs, err := jsonutil.Encode(s)
+	is.NoError(err)
+	is.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"created": {"type": "string", "format": "date-time"},
+			"name": {"type": "string"},
+			"age": {"type": "integer", "minimum": 0},
+			"score": {"type": "number"},
+			"id": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string"}},
+				"required": ["city"]
+			},
+			"extra": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"any": {},
+			"raw": {"type": "string", "format": "byte"},
+			"friends": {"type": "array", "items": {"anyOf": [{"$ref": "#"}, {"type": "null"}]}},
+			"Enabled": {"type": "boolean"}
+		},
+		"required": ["Enabled", "age", "created", "id", "name"]
+	}`, string(bs))
+
+	// validate by the generated schema
+	s, err = jsonutil.ParseSchema(bs)
+	is.NoError(err)
+
+	user := &schemaUser{Name: "inhere", Friends: []*schemaUser{{Name: "tom"}}}
+	is.NoError(s.ValidateValue(user))
+	is.Error(s.Validate([]byte(`{"name": "inhere"}`)))
+
+	// the generated schema is compiled
+	s = jsonutil.GenerateSchema(&schemaUser{})
+	is.NoError(s.ValidateValue(user))
+	is.NoError(s.ValidateValue(&schemaUser{Friends: []*schemaUser{nil}}))
+	is.Error(s.Validate([]byte(`{"name": "inhere"}`)))
+}
+
+type schemaNode struct {
+	Value    int           `json:"value"`
+	Children []*schemaNode `json:"children"`
+}
+
+type schemaTree struct {
+	Root *schemaNode `json:"root"`
+}
+
+func TestGenerateSchema_defs(t *testing.T) {
+	is := assert.New(t)
+
+	bs, err := jsonutil.Encode(jsonutil.GenerateSchema(schemaTree{}))
+	is.NoError(err)
+	is.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"root": {"anyOf": [{"$ref": "#/$defs/schemaNode"}, {"type": "null"}]}},
+		"required": ["root"],
+		"$defs": {
+			"schemaNode": {
+				"type": "object",
+				"properties": {
+					"value": {"type": "integer"},
+					"children": {
+						"type": ["array", "null"],
+						"items": {"anyOf": [{"$ref": "#/$defs/schemaNode"}, {"type": "null"}]}
+					}
+				},
+				"required": ["children", "value"]
+			}
+		}
+	}`, string(bs))
+
+	s, err := jsonutil.ParseSchema(bs)
+	is.NoError(err)
+	is.NoError(s.Validate([]byte(`{"root": {"value": 1, "children": [{"value": 2, "children": []}]}}`)))
+	is.Error(s.Validate([]byte(`{"root": {"value": 1, "children": [{"value": "2", "children": []}]}}`)))
+	is.NoError(s.Validate([]byte(`{"root": {"value": 1, "children": null}}`)))
+	is.NoError(s.Validate([]byte(`{"root": null}`)))
+}
+
+func TestSchema_multipleOf(t *testing.T) {
+	is := assert.New(t)
+	s := jsonutil.MustParseSchema([]byte(`{"multipleOf": 0.1}`))
+
+	for _, num := range []string{"0.3", "0.7", "1.1", "3", "-0.9", "1e2"} {
+		is.NoError(s.Validate([]byte(num)), num)
+	}
+	for _, num := range []string{"0.35", "1.01", "1e-5"} {
+		is.Error(s.Validate([]byte(num)), num)
+	}
+}
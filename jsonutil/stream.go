@@ -0,0 +1,366 @@
+package jsonutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/json-iterator/go"
+)
+
+// the buffer size for the stream reader and writer
+const streamBufSize = 4096
+
+// NewEncoder create JSON stream encoder by the jsonutil config
+func NewEncoder(w io.Writer) *jsoniter.Encoder {
+	return parser.NewEncoder(w)
+}
+
+// NewDecoder create JSON stream decoder by the jsonutil config
+func NewDecoder(r io.Reader) *jsoniter.Decoder {
+	return parser.NewDecoder(r)
+}
+
+//
+// ------------------ JSON array stream ------------------
+//
+
+// ArrayDecoder decode the elements of a top-level JSON array one by one,
+// without read the whole array into memory.
+//
+// Usage:
+// 	dec := jsonutil.NewArrayDecoder(file)
+// 	for dec.More() {
+// 		user := &User{}
+// 		if err := dec.Decode(user); err != nil {
+// 			return err
+// 		}
+// 	}
+// 	err := dec.Err()
+type ArrayDecoder struct {
+	iter *jsoniter.Iterator
+	// index of the current element, -1 on not started
+	index int
+	done  bool
+	err   error
+}
+
+// NewArrayDecoder create array decoder
+func NewArrayDecoder(r io.Reader) *ArrayDecoder {
+	return &ArrayDecoder{
+		iter:  jsoniter.Parse(parser, r, streamBufSize),
+		index: -1,
+	}
+}
+
+// More check and move to the next element, returns false on the array end or an error occurred.
+// NOTICE: must call Decode() or Raw() for each element before next More().
+func (d *ArrayDecoder) More() bool {
+	if d.done {
+		return false
+	}
+
+	// check the top-level value is an array or null
+	if d.index == -1 {
+		kind := d.iter.WhatIsNext()
+		if d.iter.Error == nil && kind != jsoniter.ArrayValue && kind != jsoniter.NilValue {
+			d.done = true
+			d.err = fmt.Errorf("jsonutil: decode array: expect a JSON array, but found %s value", valueTypeName(kind))
+			return false
+		}
+		d.iter.Error = nil
+	}
+
+	if !d.iter.ReadArray() {
+		d.done = true
+		d.err = d.iterErr()
+		return false
+	}
+
+	if d.err = d.iterErr(); d.err != nil {
+		d.done = true
+		return false
+	}
+
+	d.index++
+	return true
+}
+
+// Decode the current element to v
+func (d *ArrayDecoder) Decode(v interface{}) error {
+	if d.done {
+		return d.endErr()
+	}
+
+	d.iter.ReadVal(v)
+	return d.checkErr()
+}
+
+// Raw read the current element as raw JSON bytes
+func (d *ArrayDecoder) Raw() ([]byte, error) {
+	if d.done {
+		return nil, d.endErr()
+	}
+
+	raw := d.iter.SkipAndReturnBytes()
+	return bytes.TrimSpace(raw), d.checkErr()
+}
+
+// Index get the index of the current element
+func (d *ArrayDecoder) Index() int {
+	return d.index
+}
+
+// Err get the error of the decoding
+func (d *ArrayDecoder) Err() error {
+	return d.err
+}
+
+func (d *ArrayDecoder) endErr() error {
+	if d.err != nil {
+		return d.err
+	}
+	return io.EOF
+}
+
+func (d *ArrayDecoder) checkErr() error {
+	if d.err = d.iterErr(); d.err != nil {
+		d.done = true
+	}
+	return d.err
+}
+
+// the io.EOF in the array is unexpected
+func (d *ArrayDecoder) iterErr() error {
+	err := d.iter.Error
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return fmt.Errorf("jsonutil: decode array element #%d: %w", d.index+1, err)
+	}
+	return nil
+}
+
+// name of the JSON value type for the error message
+func valueTypeName(kind jsoniter.ValueType) string {
+	switch kind {
+	case jsoniter.ObjectValue:
+		return "object"
+	case jsoniter.StringValue:
+		return "string"
+	case jsoniter.NumberValue:
+		return "number"
+	case jsoniter.BoolValue:
+		return "bool"
+	}
+	return "invalid"
+}
+
+// DecodeArray decode each element of the top-level JSON array from the reader.
+// the fn will be called with the element index and raw JSON bytes.
+//
+// Usage:
+// 	err := jsonutil.DecodeArray(file, func(i int, raw []byte) error {
+// 		user := &User{}
+// 		return jsonutil.Decode(raw, user)
+// 	})
+func DecodeArray(r io.Reader, fn func(i int, raw []byte) error) error {
+	dec := NewArrayDecoder(r)
+	for dec.More() {
+		raw, err := dec.Raw()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(dec.Index(), raw); err != nil {
+			return err
+		}
+	}
+	return dec.Err()
+}
+
+// ArrayEncoder encode the elements to a JSON array incrementally.
+// must call Close() for write the array end.
+//
+// Usage:
+// 	enc := jsonutil.NewArrayEncoder(file)
+// 	for _, user := range users {
+// 		if err := enc.Encode(user); err != nil {
+// 			return err
+// 		}
+// 	}
+// 	err := enc.Close()
+type ArrayEncoder struct {
+	stream *jsoniter.Stream
+	count  int
+	closed bool
+}
+
+// NewArrayEncoder create array encoder
+func NewArrayEncoder(w io.Writer) *ArrayEncoder {
+	return &ArrayEncoder{stream: jsoniter.NewStream(parser, w, streamBufSize)}
+}
+
+// Encode write an element to the array
+func (e *ArrayEncoder) Encode(v interface{}) error {
+	if e.closed {
+		return fmt.Errorf("jsonutil: encode element on closed array encoder")
+	}
+
+	if e.count == 0 {
+		e.stream.WriteArrayStart()
+	} else {
+		e.stream.WriteMore()
+	}
+
+	e.count++
+	e.stream.WriteVal(v)
+	if e.stream.Error != nil {
+		return e.stream.Error
+	}
+
+	// flush on the buffer is full
+	if e.stream.Buffered() >= streamBufSize {
+		return e.stream.Flush()
+	}
+	return nil
+}
+
+// Count of the encoded elements
+func (e *ArrayEncoder) Count() int {
+	return e.count
+}
+
+// Close write the array end and flush the buffer. it will write "[]" on no elements.
+func (e *ArrayEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+
+	e.closed = true
+	if e.count == 0 {
+		e.stream.WriteEmptyArray()
+	} else {
+		e.stream.WriteArrayEnd()
+	}
+	return e.stream.Flush()
+}
+
+//
+// ------------------ NDJSON ------------------
+//
+
+// NDJSONReader read newline-delimited JSON(NDJSON, JSON Lines) values one by one.
+// the blank lines will be skipped.
+//
+// Usage:
+// 	rd := jsonutil.NewNDJSONReader(file)
+// 	for rd.Next() {
+// 		user := &User{}
+// 		if err := rd.Decode(user); err != nil {
+// 			return err
+// 		}
+// 	}
+// 	err := rd.Err()
+type NDJSONReader struct {
+	rd   *bufio.Reader
+	line int
+	raw  []byte
+	err  error
+}
+
+// NewNDJSONReader create NDJSON reader
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	return &NDJSONReader{rd: bufio.NewReaderSize(r, streamBufSize)}
+}
+
+// Next read the next non-blank line, returns false on EOF or an error occurred.
+func (r *NDJSONReader) Next() bool {
+	for r.err == nil {
+		line, err := r.rd.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			r.err = err
+			return false
+		}
+
+		if len(line) == 0 && err == io.EOF {
+			break
+		}
+
+		r.line++
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			r.raw = line
+			return true
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	r.raw = nil
+	return false
+}
+
+// Decode the current line to v
+func (r *NDJSONReader) Decode(v interface{}) error {
+	if err := Decode(r.raw, v); err != nil {
+		return fmt.Errorf("jsonutil: decode NDJSON line %d: %w", r.line, err)
+	}
+	return nil
+}
+
+// Bytes get the raw JSON bytes of the current line
+func (r *NDJSONReader) Bytes() []byte {
+	return r.raw
+}
+
+// Line get the line number of the current value, start from 1
+func (r *NDJSONReader) Line() int {
+	return r.line
+}
+
+// Err get the reading error, io.EOF is not an error
+func (r *NDJSONReader) Err() error {
+	return r.err
+}
+
+// ReadNDJSON read each value of the NDJSON from reader.
+// the fn will be called with the line number and raw JSON bytes.
+func ReadNDJSON(r io.Reader, fn func(line int, raw []byte) error) error {
+	rd := NewNDJSONReader(r)
+	for rd.Next() {
+		if err := fn(rd.Line(), rd.Bytes()); err != nil {
+			return err
+		}
+	}
+	return rd.Err()
+}
+
+// NDJSONWriter write values as newline-delimited JSON(NDJSON, JSON Lines)
+type NDJSONWriter struct {
+	enc *jsoniter.Encoder
+}
+
+// NewNDJSONWriter create NDJSON writer
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{enc: parser.NewEncoder(w)}
+}
+
+// Write a value as one line
+func (w *NDJSONWriter) Write(v interface{}) error {
+	return w.enc.Encode(v)
+}
+
+// WriteNDJSON write each element of the values as one line
+func WriteNDJSON(w io.Writer, vs ...interface{}) error {
+	nw := NewNDJSONWriter(w)
+	for _, v := range vs {
+		if err := nw.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
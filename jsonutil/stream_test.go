@@ -0,0 +1,148 @@
+package jsonutil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/jsonutil"
+)
+
+type streamUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestArrayDecoder(t *testing.T) {
+	is := assert.New(t)
+	src := `[{"name": "inhere", "age": 22}, {"name": "tom", "age": 23} ]`
+
+	var users []streamUser
+	dec := jsonutil.NewArrayDecoder(strings.NewReader(src))
+	for dec.More() {
+		user := streamUser{}
+		is.NoError(dec.Decode(&user))
+		users = append(users, user)
+	}
+
+	is.NoError(dec.Err())
+	is.Equal(1, dec.Index())
+	is.Equal([]streamUser{{"inhere", 22}, {"tom", 23}}, users)
+	is.Equal(io.EOF, dec.Decode(&streamUser{}))
+
+	// empty and null
+	for _, src := range []string{"[]", " [ ] ", "null"} {
+		dec = jsonutil.NewArrayDecoder(strings.NewReader(src))
+		is.False(dec.More())
+		is.NoError(dec.Err())
+	}
+
+	// bad input
+	for _, src := range []string{`{"a": 1}`, `[1, 2`, `[1 2]`, ``} {
+		dec = jsonutil.NewArrayDecoder(strings.NewReader(src))
+		for dec.More() {
+			var n int
+			_ = dec.Decode(&n)
+		}
+		is.Error(dec.Err(), src)
+	}
+	dec = jsonutil.NewArrayDecoder(strings.NewReader(`{"a": 1}`))
+	is.False(dec.More())
+	is.EqualError(dec.Err(), "jsonutil: decode array: expect a JSON array, but found object value")
+}
+
+func TestDecodeArray(t *testing.T) {
+	is := assert.New(t)
+
+	var raws []string
+	err := jsonutil.DecodeArray(strings.NewReader(`[1, "a", {"b": [2]}]`), func(i int, raw []byte) error {
+		raws = append(raws, string(raw))
+		return nil
+	})
+	is.NoError(err)
+	is.Equal([]string{"1", `"a"`, `{"b": [2]}`}, raws)
+
+	stopErr := errors.New("stop")
+	err = jsonutil.DecodeArray(strings.NewReader(`[1, 2, 3]`), func(i int, raw []byte) error {
+		if i == 1 {
+			return stopErr
+		}
+		return nil
+	})
+	is.Equal(stopErr, err)
+}
+
+func TestArrayEncoder(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	enc := jsonutil.NewArrayEncoder(buf)
+	is.NoError(enc.Close())
+	is.Equal("[]", buf.String())
+
+	buf.Reset()
+	enc = jsonutil.NewArrayEncoder(buf)
+	is.NoError(enc.Encode(streamUser{"inhere", 22}))
+	is.NoError(enc.Encode(23))
+	is.Equal(2, enc.Count())
+	is.NoError(enc.Close())
+	is.NoError(enc.Close())
+	is.Error(enc.Encode(1))
+	is.Equal(`[{"name":"inhere","age":22},23]`, buf.String())
+
+	// encode large data, the buffer will be flushed
+	buf.Reset()
+	enc = jsonutil.NewArrayEncoder(buf)
+	for i := 0; i < 1000; i++ {
+		is.NoError(enc.Encode(streamUser{"inhere", i}))
+	}
+	is.NotEmpty(buf.Len())
+	is.NoError(enc.Close())
+
+	var users []streamUser
+	is.NoError(jsonutil.Decode(buf.Bytes(), &users))
+	is.Len(users, 1000)
+	is.Equal(999, users[999].Age)
+}
+
+func TestNDJSON(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	err := jsonutil.WriteNDJSON(buf, streamUser{"inhere", 22}, streamUser{"tom", 23})
+	is.NoError(err)
+	is.Equal("{\"name\":\"inhere\",\"age\":22}\n{\"name\":\"tom\",\"age\":23}\n", buf.String())
+
+	// with blank lines and no newline at end
+	buf.WriteString("\n  \n{\"name\":\"john\",\"age\":24}")
+
+	var users []streamUser
+	rd := jsonutil.NewNDJSONReader(buf)
+	for rd.Next() {
+		user := streamUser{}
+		is.NoError(rd.Decode(&user))
+		users = append(users, user)
+	}
+	is.NoError(rd.Err())
+	is.Equal(5, rd.Line())
+	is.Equal([]streamUser{{"inhere", 22}, {"tom", 23}, {"john", 24}}, users)
+
+	// error with line number
+	rd = jsonutil.NewNDJSONReader(strings.NewReader("1\n\n{bad}\n"))
+	is.True(rd.Next())
+	is.True(rd.Next())
+	err = rd.Decode(&users)
+	is.Error(err)
+	is.Contains(err.Error(), "line 3")
+
+	var lines []int
+	err = jsonutil.ReadNDJSON(strings.NewReader("1\n2\n\n3"), func(line int, raw []byte) error {
+		lines = append(lines, line)
+		return nil
+	})
+	is.NoError(err)
+	is.Equal([]int{1, 2, 4}, lines)
+}
@@ -26,6 +26,11 @@ func DataSize(size uint64) string {
 	return fmtutil.DataSize(size)
 }
 
+// ParseDataSize parse data size string to bytes. alias format.ParseDataSize()
+func ParseDataSize(s string) (uint64, error) {
+	return fmtutil.ParseDataSize(s)
+}
+
 // HowLongAgo calc time. alias format.HowLongAgo()
 func HowLongAgo(sec int64) string {
 	return fmtutil.HowLongAgo(sec)
@@ -23,6 +23,10 @@ func TestElapsedTime(t *testing.T) {
 
 func TestDataSize(t *testing.T) {
 	assert.Equal(t, "3.38K", mathutil.DataSize(3456))
+
+	size, err := mathutil.ParseDataSize("3.38K")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3461), size)
 }
 
 func TestHowLongAgo(t *testing.T) {
@@ -17,3 +17,84 @@ const (
 	MIMEMSGPACK  = "application/x-msgpack"
 	MIMEMSGPACK2 = "application/msgpack"
 )
+
+// MIME of the common text and script file formats.
+const (
+	MIMECSS      = "text/css"
+	MIMECSV      = "text/csv"
+	MIMETSV      = "text/tab-separated-values"
+	MIMEMarkdown = "text/markdown"
+	MIMETOML     = "application/toml"
+	MIMEJS       = "application/javascript"
+	MIMEWASM     = "application/wasm"
+)
+
+// MIME of the common document and archive file formats.
+const (
+	MIMEPDF   = "application/pdf"
+	MIMERTF   = "application/rtf"
+	MIMEZIP   = "application/zip"
+	MIMEGZIP  = "application/gzip"
+	MIMEBZIP2 = "application/x-bzip2"
+	MIMEXZ    = "application/x-xz"
+	MIMEZSTD  = "application/zstd"
+	MIME7Z    = "application/x-7z-compressed"
+	MIMERAR   = "application/vnd.rar"
+	MIMETAR   = "application/x-tar"
+	MIMEJAR   = "application/java-archive"
+	MIMEAPK   = "application/vnd.android.package-archive"
+	MIMEEPUB  = "application/epub+zip"
+
+	MIMEDOC  = "application/msword"
+	MIMEXLS  = "application/vnd.ms-excel"
+	MIMEPPT  = "application/vnd.ms-powerpoint"
+	MIMEDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MIMEPPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	MIMEODT  = "application/vnd.oasis.opendocument.text"
+	MIMEODS  = "application/vnd.oasis.opendocument.spreadsheet"
+	MIMEODP  = "application/vnd.oasis.opendocument.presentation"
+	MIMEOLE  = "application/x-ole-storage"
+
+	MIMESQLite  = "application/vnd.sqlite3"
+	MIMEParquet = "application/vnd.apache.parquet"
+	MIMEELF     = "application/x-elf"
+	MIMEEXE     = "application/vnd.microsoft.portable-executable"
+	MIMEClass   = "application/java-vm"
+	MIMEBinary  = "application/octet-stream"
+)
+
+// MIME of the common image, audio, video and font file formats.
+const (
+	MIMEPNG  = "image/png"
+	MIMEJPEG = "image/jpeg"
+	MIMEGIF  = "image/gif"
+	MIMEBMP  = "image/bmp"
+	MIMEWEBP = "image/webp"
+	MIMEICO  = "image/x-icon"
+	MIMETIFF = "image/tiff"
+	MIMESVG  = "image/svg+xml"
+	MIMEHEIC = "image/heic"
+	MIMEHEIF = "image/heif"
+	MIMEAVIF = "image/avif"
+	MIMEPSD  = "image/vnd.adobe.photoshop"
+
+	MIMEMP3  = "audio/mpeg"
+	MIMEWAV  = "audio/wav"
+	MIMEFLAC = "audio/flac"
+	MIMEOGG  = "audio/ogg"
+	MIMEMIDI = "audio/midi"
+	MIMEM4A  = "audio/mp4"
+
+	MIMEMP4  = "video/mp4"
+	MIMEMOV  = "video/quicktime"
+	MIMEAVI  = "video/x-msvideo"
+	MIMEWEBM = "video/webm"
+	MIMEMKV  = "video/x-matroska"
+	MIMEFLV  = "video/x-flv"
+
+	MIMEWOFF  = "font/woff"
+	MIMEWOFF2 = "font/woff2"
+	MIMETTF   = "font/ttf"
+	MIMEOTF   = "font/otf"
+)
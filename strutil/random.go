@@ -8,8 +8,9 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
-	"io/ioutil"
+	"io"
 	mathRand "math/rand"
+	"os"
 	"time"
 )
 
@@ -45,13 +46,18 @@ func Md5(src interface{}) string {
 	return GenMd5(src)
 }
 
+// Md5File get md5 string of the file contents, will panic on error
 func Md5File(path string) string {
-	content, err := ioutil.ReadFile(path)
+	file, err := os.Open(path)
 	if err != nil {
 		panic(err)
 	}
+	defer file.Close()
+
 	h := md5.New()
-	h.Write(content)
+	if _, err = io.Copy(h, file); err != nil {
+		panic(err)
+	}
 	return hex.EncodeToString(h.Sum(nil))
 }
 
@@ -6,7 +6,6 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -16,6 +15,8 @@ import (
 	"time"
 
 	uuid "github.com/iris-contrib/go.uuid"
+	"github.com/urionz/goutil/fmtutil"
+	"github.com/urionz/goutil/jsonutil"
 )
 
 // Position for padding string
@@ -192,10 +193,9 @@ func Replaces(str string, pairs map[string]string) string {
 
 // PrettyJSON get pretty Json string
 // Deprecated
-//  please use fmtutil.PrettyJSON() or jsonutil.Pretty() instead it
+//  please use jsonutil.Pretty() or jsonutil.PrettyWith() instead it
 func PrettyJSON(v interface{}) (string, error) {
-	out, err := json.MarshalIndent(v, "", "    ")
-	return string(out), err
+	return jsonutil.Pretty(v)
 }
 
 // RenderTemplate render text template
@@ -228,6 +228,9 @@ func RenderText(input string, data interface{}, fns template.FuncMap, isFile ...
 		},
 	})
 
+	// number format functions. eg: {{ number .Total 2 }}
+	t.Funcs(fmtutil.NumberFuncs())
+
 	// custom add template functions
 	if len(fns) > 0 {
 		t.Funcs(fns)
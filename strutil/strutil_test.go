@@ -153,3 +153,16 @@ func TestPrettyJSON(t *testing.T) {
 		assert.Equal(t, want, got)
 	}
 }
+
+func TestRenderText_numberFuncs(t *testing.T) {
+	tpl := `{{ number .Total 2 }} {{ compactNum .Views }} {{ ordinal .Rank }} {{ percent .Rate 1 }} {{ dataSize .Size }}`
+	data := map[string]interface{}{
+		"Total": 1234567.891,
+		"Views": 3400000,
+		"Rank":  2,
+		"Rate":  45.67,
+		"Size":  1536,
+	}
+
+	assert.Equal(t, "1,234,567.89 3.4M 2nd 45.7% 1.50K", strutil.RenderText(tpl, data, nil))
+}
@@ -3,15 +3,14 @@ package sysutil
 import (
 	"bytes"
 	"os/exec"
-
-	"github.com/urionz/goutil/strutil"
+	"strings"
 )
 
 // QuickExec quick exec an simple command line
 // Usage:
 //	QuickExec("git status")
 func QuickExec(cmdLine string, workDir ...string) (string, error) {
-	ss := strutil.Split(cmdLine, " ")
+	ss := strings.Fields(cmdLine)
 
 	return ExecCmd(ss[0], ss[1:], workDir...)
 }
@@ -0,0 +1,29 @@
+package testutil
+
+import (
+	"github.com/urionz/goutil/fsutil"
+)
+
+// FixtureDir create a temp dir and seeding files from map[path]contents.
+// the dir will be auto removed by t.Cleanup()
+//
+// Usage:
+// 	dir := FixtureDir(t, map[string]string{
+// 		"config/app.json": `{"debug": true}`,
+// 		"logs/": "", // empty dir
+// 	})
+func FixtureDir(t fsutil.TB, files map[string]string) string {
+	t.Helper()
+	return fsutil.TempTreeT(t, files)
+}
+
+// NewFixtureTree create a fixture tree builder.
+//
+// Usage:
+// 	dir := NewFixtureTree().
+// 		File("go.mod", "module demo").
+// 		Dir("tmp").
+// 		BuildT(t)
+func NewFixtureTree() *fsutil.FixtureTree {
+	return fsutil.NewFixtureTree()
+}
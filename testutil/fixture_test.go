@@ -0,0 +1,29 @@
+package testutil_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urionz/goutil/fsutil"
+	"github.com/urionz/goutil/testutil"
+)
+
+func TestFixtureDir(t *testing.T) {
+	var dir string
+	t.Run("fixture", func(t *testing.T) {
+		dir = testutil.FixtureDir(t, map[string]string{
+			"config/app.json": `{"debug": true}`,
+			"logs/":           "",
+		})
+
+		assert.True(t, fsutil.IsFile(filepath.Join(dir, "config/app.json")))
+		assert.True(t, fsutil.IsDir(filepath.Join(dir, "logs")))
+	})
+
+	// removed by cleanup
+	assert.False(t, fsutil.PathExists(dir))
+
+	dir = testutil.NewFixtureTree().File("go.mod", "module demo").BuildT(t)
+	assert.True(t, fsutil.IsFile(filepath.Join(dir, "go.mod")))
+}
@@ -3,6 +3,7 @@ package goutil
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -12,7 +13,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/urionz/goutil/jsonutil"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/mod/modfile"
 )
@@ -47,8 +47,8 @@ func ValidatePassword(encodePassword, inputPassword string) bool {
 // model 定义表模型的数据结构体
 // 相当于是在合并两个结构体(data 必须是 model 的子集)
 func Filling(form interface{}, model interface{}) error {
-	jsonBytes, _ := jsonutil.Encode(form)
-	return jsonutil.Decode(jsonBytes, model)
+	jsonBytes, _ := json.Marshal(form)
+	return json.Unmarshal(jsonBytes, model)
 }
 
 // FuncName get func name